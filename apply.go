@@ -4,7 +4,10 @@
 
 package set
 
-import "sort"
+import (
+	"context"
+	"sort"
+)
 
 // Pivots transforms set-relative sizes into data-absolute pivots. Pivots is
 // mostly only useful in conjunction with Apply. The sizes slice sizes may
@@ -25,8 +28,8 @@ func Pivots(sizes ...int) []int {
 // order to make initializing the pivots slice simpler.
 //
 // data.Swap and data.Less are assumed to be concurrent-safe. Only
-// associative operations should be used (Diff is not associative); see the
-// Apply (Diff) example for a workaround. The result of applying SymDiff
+// associative operations should be used (Diff is not associative); use
+// ApplyDiff to subtract many sets from one. The result of applying SymDiff
 // will contain elements that exist in an odd number of sets.
 //
 // The implementation runs op concurrently on pairs of neighbor sets
@@ -35,14 +38,63 @@ func Pivots(sizes ...int) []int {
 // remains. The process is adaptive (large sets will not prevent small pairs
 // from being processed), and strives for data-locality (only adjacent
 // neighbors are paired and data shifts toward the zero index).
+//
+// When there are many small sets, ApplyK may be faster.
 func Apply(op Op, data sort.Interface, pivots []int) (size int) {
+	size, _ = ApplyOptions{}.Apply(op, data, pivots)
+	return size
+}
+
+// ApplyDiff removes the elements of every other set terminated by pivots
+// from the first set; the resulting set will occupy [0:size]. pivots has
+// the same meaning as in Apply. The sets being subtracted are first
+// combined concurrently, as by Apply with Union, and data.Swap and
+// data.Less are likewise assumed to be concurrent-safe.
+func ApplyDiff(data sort.Interface, pivots []int) (size int) {
+	size, _ = ApplyOptions{}.ApplyDiff(data, pivots)
+	return size
+}
+
+// ApplyOptions bounds the resources used by Apply. The zero value imposes
+// no bounds.
+type ApplyOptions struct {
+	// MaxWorkers, if positive, limits the number of goroutines running op
+	// at any one time.
+	MaxWorkers int
+
+	// Context, if non-nil, can be used to abandon the call, in which case
+	// Apply returns Context.Err() once any ops already running have
+	// finished. No new ops are started after cancellation is noticed.
+	Context context.Context
+
+	// SequentialBelow is the combined size of a pair of sets below which op
+	// is run directly by the calling goroutine rather than by a new one.
+	SequentialBelow int
+}
+
+// Apply is like the Apply function, but abides by the options in o. If an
+// error is returned, size is zero and data will have been rearranged
+// arbitrarily.
+func (o ApplyOptions) Apply(op Op, data sort.Interface, pivots []int) (size int, err error) {
+	if debug {
+		assertPivots("Apply", data, pivots)
+		defer func() { assertOut("Apply", data, size, data.Len()) }()
+	}
+	ctx := o.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err = ctx.Err(); err != nil {
+		return 0, err
+	}
+
 	switch len(pivots) {
 	case 0:
-		return 0
+		return 0, nil
 	case 1:
-		return pivots[0]
+		return pivots[0], nil
 	case 2:
-		return op(data, pivots[0])
+		return op(data, pivots[0]), nil
 	}
 
 	spans := make([]span, 0, len(pivots)+1)
@@ -60,27 +112,72 @@ func Apply(op Op, data sort.Interface, pivots []int) (size int) {
 	// true if the span is being used
 	inuse := make([]bool, n)
 
-	ch := make(chan span, m)
+	// the id of each span; spans cannot be told apart by position alone,
+	// since empty spans may share a start with their neighbors
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = i
+	}
+
+	// results sent by goroutines, and the number of goroutines running
+	ch := make(chan part, m)
+	running := 0
+
+	// results of ops run by this goroutine (or initial spans to pair up)
+	var done []part
+
+	// available spans that could not be paired due to MaxWorkers
+	var waiting []part
 
 	// reverse iterate over every other span, starting with the last;
 	// concurrent algo (further below) will pick available pairs operate on
 	for i := range spans[:m] {
 		i = len(spans) - 1 - i*2
-		ch <- spans[i]
+		done = append(done, part{i, spans[i]})
 	}
 
-	for s := range ch {
-		if len(spans) == 1 {
+	for {
+		if err = ctx.Err(); err != nil {
+			for ; running > 0; running-- {
+				<-ch
+			}
+			return 0, err
+		}
+
+		var r part
+		retry := false
+		switch {
+		case len(done) > 0:
+			r, done = done[0], done[1:]
+		case len(waiting) > 0 && (o.MaxWorkers <= 0 || running < o.MaxWorkers):
+			r, waiting = waiting[0], waiting[1:]
+			retry = true
+		default:
+			select {
+			case r = <-ch:
+				running--
+			case <-ctx.Done():
+				continue
+			}
+		}
+
+		// locate the span we received
+		s := r.span
+		i := sort.SearchInts(ids, r.id)
+
+		if retry {
+			// skip the span if it has since been paired
+			if i == len(ids) || ids[i] != r.id || spans[i] != s || inuse[i] {
+				continue
+			}
+		} else if len(spans) == 1 {
 			if s.i != 0 {
 				panic("impossible final span")
 			}
 			// this was the last operation
-			return s.j
+			return s.j, nil
 		}
 
-		// locate the span we received (match on start of span only)
-		i := sort.Search(len(spans), func(i int) bool { return spans[i].i >= s.i })
-
 		// store the result (this may change field j but not field i)
 		spans[i] = s
 
@@ -99,24 +196,23 @@ func Apply(op Op, data sort.Interface, pivots []int) (size int) {
 			continue
 		}
 
-		s, t := spans[i], spans[j]
-
-		go func(s, t span) {
-			// sizes of the respective sets
-			k, l := s.j-s.i, t.j-t.i
-
-			// shift the right-hand span to be adjacent to the left
-			slide(data, s.j, t.i, l)
+		id, t := ids[i], spans[j]
+		s = spans[i]
 
-			// prepare a view of the data (abs -> rel indices)
-			b := boundspan{data, span{s.i, s.j + l}}
-
-			// store result of op, adjusting for view (rel -> abs)
-			s.j = s.i + op(b, k)
-
-			// send the result back to the coordinating goroutine
-			ch <- s
-		}(s, t)
+		switch {
+		case s.j-s.i+t.j-t.i < o.SequentialBelow:
+			done = append(done, part{id, applyPair(op, data, s, t)})
+		case o.MaxWorkers <= 0 || running < o.MaxWorkers:
+			running++
+			go func(s, t span) {
+				// send the result back to the coordinating goroutine
+				ch <- part{id, applyPair(op, data, s, t)}
+			}(s, t)
+		default:
+			// try again once a worker is free
+			waiting = append(waiting, r)
+			continue
+		}
 
 		// account for the spawn merging that will occur
 		s.j += t.j - t.i
@@ -128,6 +224,54 @@ func Apply(op Op, data sort.Interface, pivots []int) (size int) {
 
 		// (and the merged span is now in use as well)
 		inuse = append(append(inuse[:i], true), inuse[k:]...)
+
+		// (keeping the id of the leftmost span)
+		ids = append(ids[:i+1], ids[k:]...)
 	}
-	panic("unreachable")
+}
+
+// part is a span of data holding a set, identified by the index of the
+// first of the original sets that were combined into it.
+type part struct {
+	id int
+	span
+}
+
+// ApplyDiff is like the ApplyDiff function, but abides by the options in
+// o. If an error is returned, size is zero and data will have been
+// rearranged arbitrarily.
+func (o ApplyOptions) ApplyDiff(data sort.Interface, pivots []int) (size int, err error) {
+	if len(pivots) < 2 {
+		return o.Apply(Diff, data, pivots)
+	}
+
+	// shift the pivots of the subtracted sets to be relative to their view
+	k, l := pivots[0], pivots[len(pivots)-1]
+	rest := make([]int, len(pivots)-1)
+	for i, j := range pivots[1:] {
+		rest[i] = j - k
+	}
+
+	n, err := o.Apply(Union, boundspan{data, span{k, l}}, rest)
+	if err != nil {
+		return 0, err
+	}
+	return Diff(boundspan{data, span{0, k + n}}, k), nil
+}
+
+// applyPair runs op on the sets s and t, where s precedes t, returning the
+// span of the resulting set.
+func applyPair(op Op, data sort.Interface, s, t span) span {
+	// sizes of the respective sets
+	k, l := s.j-s.i, t.j-t.i
+
+	// shift the right-hand span to be adjacent to the left
+	slide(data, s.j, t.i, l)
+
+	// prepare a view of the data (abs -> rel indices)
+	b := boundspan{data, span{s.i, s.j + l}}
+
+	// store result of op, adjusting for view (rel -> abs)
+	s.j = s.i + op(b, k)
+	return s
 }
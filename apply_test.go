@@ -5,12 +5,180 @@
 package set_test
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"sync/atomic"
+	"testing"
 
 	"github.com/xtgo/set"
+	td "github.com/xtgo/set/internal/testdata"
 )
 
+func concat(sets [][]int) sort.IntSlice {
+	var data sort.IntSlice
+	for _, set := range sets {
+		data = append(data, set...)
+	}
+	return data
+}
+
+func TestApplyK(t *testing.T) {
+	tasks := []struct {
+		name string
+		op   set.Op
+		kop  set.KOp
+	}{
+		{"Union", set.Union, set.UnionK},
+		{"Inter", set.Inter, set.InterK},
+		{"SymDiff", set.SymDiff, set.SymDiffK},
+	}
+
+	inputs := [][][]int{
+		nil,
+		{{1, 2, 3}},
+		{nil, {1, 2}},
+		{nil, nil, {1, 2, 3}},
+		{{1, 2}, nil, nil, {2, 3}, nil},
+		{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}},
+		td.Rand(16, td.Small),
+		td.Alternate(5, td.Small),
+		td.Overlap(3, td.Small),
+	}
+
+	for _, task := range tasks {
+		for _, sets := range inputs {
+			pivots := pivots(sets)
+
+			data := concat(sets)
+			want := data[:set.Apply(task.op, data, pivots)]
+
+			data = concat(sets)
+			got := data[:set.ApplyK(task.kop, data, pivots)]
+
+			if !td.IsEqual(got, want) {
+				t.Errorf("ApplyK(%s, %v) = %v, want %v", task.name, sets, got, want)
+			}
+
+			data = concat(sets)
+			if n := set.ApplyLen(task.kop, data, pivots); n != len(want) || !td.IsEqual(data, concat(sets)) {
+				t.Errorf("ApplyLen(%s, %v) = %d, want %d", task.name, sets, n, len(want))
+			}
+		}
+	}
+}
+
+func TestApplyThreshold(t *testing.T) {
+	sets := td.Rand(12, td.Small)
+	pivots := pivots(sets)
+
+	counts := map[int]int{}
+	for _, s := range sets {
+		for _, x := range s {
+			counts[x]++
+		}
+	}
+
+	for lo := 0; lo <= len(sets)+1; lo++ {
+		for hi := -1; hi <= len(sets)+1; hi++ {
+			var want []int
+			for x, k := range counts {
+				if k >= lo && (hi < 0 || k <= hi) {
+					want = append(want, x)
+				}
+			}
+			sort.Ints(want)
+
+			data := concat(sets)
+			got := data[:set.ApplyThreshold(data, pivots, lo, hi)]
+
+			if !td.IsEqual(got, want) {
+				t.Errorf("ApplyThreshold(%v, %d, %d) = %v, want %v", sets, lo, hi, got, want)
+			}
+		}
+	}
+}
+
+func ExampleApplyThreshold() {
+	// listings reported by three replicas
+	sets := []sort.StringSlice{
+		{"a.txt", "b.txt", "c.txt"},
+		{"a.txt", "c.txt", "d.txt"},
+		{"a.txt", "b.txt", "e.txt"},
+	}
+
+	var data sort.StringSlice
+	pivots := make([]int, len(sets))
+	for i, set := range sets {
+		pivots[i] = len(set)
+		data = append(data, set...)
+	}
+	pivots = set.Pivots(pivots...)
+
+	// keep the files that a majority of replicas agree on
+	size := set.ApplyThreshold(data, pivots, len(sets)/2+1, -1)
+	fmt.Println(data[:size])
+
+	// Output: [a.txt b.txt c.txt]
+}
+
+func TestApplyOptions(t *testing.T) {
+	sets := td.Rand(64, td.Small)
+	pivots := pivots(sets)
+
+	data := concat(sets)
+	want := data[:set.ApplyK(set.UnionK, data, pivots)]
+
+	for _, workers := range []int{0, 1, 3} {
+		for _, seq := range []int{0, td.Small, 1 << 30} {
+			var running, peak int32
+			op := func(data sort.Interface, pivot int) int {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for p := atomic.LoadInt32(&peak); n > p; p = atomic.LoadInt32(&peak) {
+					atomic.CompareAndSwapInt32(&peak, p, n)
+				}
+				return set.Union(data, pivot)
+			}
+
+			opts := set.ApplyOptions{MaxWorkers: workers, SequentialBelow: seq}
+			data := concat(sets)
+			size, err := opts.Apply(op, data, pivots)
+
+			if err != nil || !td.IsEqual(data[:size], want) {
+				t.Errorf("%+v: got %v, %v, want %v", opts, data[:size], err, want)
+			}
+			if workers > 0 && int(peak) > workers {
+				t.Errorf("%+v: %d ops ran concurrently", opts, peak)
+			}
+		}
+	}
+}
+
+func TestApplyOptionsCancel(t *testing.T) {
+	sets := td.Rand(64, td.Small)
+	pivots := pivots(sets)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	op := func(data sort.Interface, pivot int) int {
+		if atomic.AddInt32(&calls, 1) == 4 {
+			cancel()
+		}
+		return set.Union(data, pivot)
+	}
+
+	opts := set.ApplyOptions{MaxWorkers: 2, Context: ctx}
+	size, err := opts.Apply(op, concat(sets), pivots)
+
+	if err != context.Canceled || size != 0 {
+		t.Errorf("Apply = %d, %v, want 0, %v", size, err, context.Canceled)
+	}
+	if n := atomic.LoadInt32(&calls); n >= int32(len(sets)-1) {
+		t.Errorf("all %d ops ran despite cancellation", n)
+	}
+}
+
 func ExampleApply() {
 	sets := []sort.IntSlice{
 		{1, 3, 5, 7, 9},  // odds from 1
@@ -54,6 +222,62 @@ func ExampleApply() {
 	// sdiff: [1 2 3 7 10 15 20]
 }
 
+func TestApplyDiff(t *testing.T) {
+	inputs := [][][]int{
+		nil,
+		{{1, 2, 3}},
+		{{1, 2, 3}, nil},
+		{nil, {1, 2}, {3}},
+		{{1, 2, 3, 4, 5}, {2}, {4, 6}},
+		td.Rand(16, td.Small),
+		td.Overlap(4, td.Small),
+	}
+
+	for _, sets := range inputs {
+		want := concat(sets[:min(len(sets), 1)])
+		for _, s := range sets[min(len(sets), 1):] {
+			want = set.IntsDo(set.Diff, want, s...)
+		}
+
+		data := concat(sets)
+		got := data[:set.ApplyDiff(data, pivots(sets))]
+
+		if !td.IsEqual(got, want) {
+			t.Errorf("ApplyDiff(%v) = %v, want %v", sets, got, want)
+		}
+	}
+}
+
+func ExampleApplyDiff() {
+	sets := []sort.IntSlice{
+		{0, 2, 4, 6, 8, 10},  // positive evens
+		{0, 1, 2, 3, 5, 8},   // set of fibonacci numbers
+		{5, 10, 15},          // positive 5-multiples
+		{2, 3, 5, 7, 11, 13}, // primes
+	}
+
+	pivots := make([]int, len(sets))
+	var data sort.IntSlice
+
+	// concatenate the sets together for use with the set package
+	for i, set := range sets {
+		pivots[i] = len(set)
+		data = append(data, set...)
+	}
+
+	// transform set sizes into pivots
+	pivots = set.Pivots(pivots...)
+
+	// calculate a - b - c - d
+	size := set.ApplyDiff(data, pivots)
+	data = data[:size]
+
+	fmt.Println("diff:", data)
+
+	// Output:
+	// diff: [4 6]
+}
+
 func ExampleApply_diff() {
 	// a -  b - c - d  cannot be used with Apply (Diff is non-associative)
 	// a - (b + c + d) equivalent, using Apply (Union is associative)
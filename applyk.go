@@ -0,0 +1,144 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// The KOp type represents a k-way operation, as used by ApplyK. A KOp
+// reports whether an element found in k of the n input sets belongs in the
+// result.
+type KOp func(k, n int) bool
+
+// UnionK selects elements present in any of the sets.
+func UnionK(k, n int) bool { return k > 0 }
+
+// InterK selects elements present in all of the sets.
+func InterK(k, n int) bool { return k == n }
+
+// SymDiffK selects elements present in an odd number of the sets.
+func SymDiffK(k, n int) bool { return k%2 == 1 }
+
+// Threshold returns a KOp selecting elements present in at least lo and at
+// most hi of the sets; if hi is negative, there is no upper bound. Union,
+// intersection, and exclusive union (elements present in exactly one set)
+// of n sets are equivalent to Threshold(1, -1), Threshold(n, n), and
+// Threshold(1, 1), respectively, and Threshold(n/2+1, -1) selects elements
+// present in a majority of the sets.
+func Threshold(lo, hi int) KOp {
+	return func(k, n int) bool {
+		return k >= lo && (hi < 0 || k <= hi)
+	}
+}
+
+// ApplyThreshold applies Threshold(lo, hi) to all the sets terminated by
+// pivots, as ApplyK does.
+func ApplyThreshold(data sort.Interface, pivots []int, lo, hi int) (size int) {
+	return ApplyK(Threshold(lo, hi), data, pivots)
+}
+
+// ApplyK applies op to all the sets terminated by pivots, which have the
+// same meaning as in Apply; the resulting set will occupy [0:size].
+//
+// Rather than pairing sets off as Apply does, ApplyK merges all of the
+// sets at once in a single sequential pass, using a heap of cursors, and
+// then moves the selected elements into place. This avoids the goroutine
+// and data movement overhead Apply incurs for each pair, which dominates
+// when there are many small sets, at the cost of allocating O(Len) ints.
+func ApplyK(op KOp, data sort.Interface, pivots []int) (size int) {
+	n := len(pivots)
+	var idx []int
+	kmerge(data, pivots, func(i, k int) {
+		if op(k, n) {
+			idx = append(idx, i)
+		}
+	})
+	permute(data, idx)
+	return len(idx)
+}
+
+// ApplyLen returns the size ApplyK would return when applying op to the
+// sets terminated by pivots, without modifying data. Unlike ApplyK, it
+// does not allocate O(Len) ints. The result also matches the size returned
+// by Apply with the corresponding Op, such as Union for UnionK.
+func ApplyLen(op KOp, data sort.Interface, pivots []int) (size int) {
+	n := len(pivots)
+	kmerge(data, pivots, func(i, k int) {
+		if op(k, n) {
+			size++
+		}
+	})
+	return size
+}
+
+// kmerge calls fn, in sorted order, for each distinct element in the sets
+// terminated by pivots, passing the index of one instance of that element,
+// and the number of sets containing it. data is not modified.
+func kmerge(data sort.Interface, pivots []int, fn func(i, k int)) {
+	h := &cursors{data: data}
+	i := 0
+	for _, j := range pivots {
+		if i < j {
+			h.spans = append(h.spans, span{i, j})
+		}
+		i = j
+	}
+	heap.Init(h)
+
+	for len(h.spans) > 0 {
+		// consume every head equal to the least head, x
+		x, k := h.spans[0].i, 0
+		for len(h.spans) > 0 && !data.Less(x, h.spans[0].i) {
+			k++
+			s := &h.spans[0]
+			if s.i++; s.i < s.j {
+				heap.Fix(h, 0)
+			} else {
+				heap.Pop(h)
+			}
+		}
+		fn(x, k)
+	}
+}
+
+// cursors is a min-heap of spans, ordered by their first elements.
+type cursors struct {
+	data  sort.Interface
+	spans []span
+}
+
+func (h *cursors) Len() int           { return len(h.spans) }
+func (h *cursors) Less(a, b int) bool { return h.data.Less(h.spans[a].i, h.spans[b].i) }
+func (h *cursors) Swap(a, b int)      { h.spans[a], h.spans[b] = h.spans[b], h.spans[a] }
+func (h *cursors) Push(x interface{}) { h.spans = append(h.spans, x.(span)) }
+
+func (h *cursors) Pop() interface{} {
+	n := len(h.spans) - 1
+	s := h.spans[n]
+	h.spans = h.spans[:n]
+	return s
+}
+
+// permute swaps the element originally at idx[r] into index r, for each r.
+func permute(data sort.Interface, idx []int) {
+	n := data.Len()
+	pos := make([]int, n)  // current index of each original element
+	orig := make([]int, n) // original index of each current element
+	for i := range pos {
+		pos[i], orig[i] = i, i
+	}
+	for r, x := range idx {
+		i := pos[x]
+		if i == r {
+			continue
+		}
+		data.Swap(r, i)
+		y := orig[r]
+		orig[r], orig[i] = x, y
+		pos[x], pos[y] = r, i
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The assertions below are only called when debug is true, which requires
+// building with the setdebug tag. They panic with a message naming the
+// function and the offending indices, to help track down broken Less and
+// Swap methods, and callers that pass unsorted data.
+
+// assertIn panics if the input to fn is invalid, as reported by Validate.
+func assertIn(fn string, data sort.Interface, pivot int) {
+	if err := Validate(data, pivot); err != nil {
+		if err == ErrPivotRange {
+			err = fmt.Errorf("set: pivot %d out of range [0:%d]", pivot, data.Len())
+		}
+		panic(fmt.Sprintf("set: %s: invalid input: %s", fn, reason(err)))
+	}
+}
+
+// assertSorted panics if [0:Len] is not sorted, as required by Uniq.
+func assertSorted(fn string, data sort.Interface) {
+	for k := 1; k < data.Len(); k++ {
+		if data.Less(k, k-1) {
+			panic(fmt.Sprintf("set: %s: invalid input: %s", fn, reason(ErrUnsorted{k})))
+		}
+	}
+}
+
+// assertPivots panics if pivots are not valid for Apply.
+func assertPivots(fn string, data sort.Interface, pivots []int) {
+	i, n := 0, data.Len()
+	for k, j := range pivots {
+		if j < i || j > n || (k == len(pivots)-1 && j != n) {
+			panic(fmt.Sprintf("set: %s: invalid input: pivots[%d] = %d out of range [%d:%d]", fn, k, j, i, n))
+		}
+		if err := validate(data, i, j); err != nil {
+			panic(fmt.Sprintf("set: %s: invalid input: set %d: %s", fn, k, reason(err)))
+		}
+		i = j
+	}
+}
+
+// assertOut panics if the output of fn, [0:size], is not a valid set of at
+// most max elements.
+func assertOut(fn string, data sort.Interface, size, max int) {
+	if size < 0 || size > max {
+		panic(fmt.Sprintf("set: %s: invalid output: size %d out of range [0:%d]", fn, size, max))
+	}
+	if err := validate(data, 0, size); err != nil {
+		panic(fmt.Sprintf("set: %s: invalid output: %s", fn, reason(err)))
+	}
+}
+
+// reason returns the message of err without its package prefix.
+func reason(err error) string {
+	return strings.TrimPrefix(err.Error(), "set: ")
+}
+
+// assertParts panics if any of [0:i], [i:j] and [j:size] is not a valid
+// set, or if the boundaries are out of order.
+func assertParts(fn string, data sort.Interface, i, j, size int) {
+	if i < 0 || i > j || j > size || size > data.Len() {
+		panic(fmt.Sprintf("set: %s: invalid output: boundaries %d, %d, %d out of order", fn, i, j, size))
+	}
+	for _, s := range []span{{0, i}, {i, j}, {j, size}} {
+		if err := validate(data, s.i, s.j); err != nil {
+			panic(fmt.Sprintf("set: %s: invalid output: %s", fn, reason(err)))
+		}
+	}
+}
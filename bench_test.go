@@ -31,12 +31,30 @@ func BenchmarkSymDiff64K(b *testing.B)      { benchMut(b, "SymDiff", td.Overlap(
 func BenchmarkSymDiff_alt32(b *testing.B)   { benchMut(b, "SymDiff", td.Alternate(2, td.Small)) }
 func BenchmarkSymDiff_alt64K(b *testing.B)  { benchMut(b, "SymDiff", td.Alternate(2, td.Large)) }
 
+func BenchmarkInter_skew32_64K(b *testing.B)   { benchMut(b, "Inter", td.Skew(td.Small, td.Large)) }
+func BenchmarkInter_skew64K_32(b *testing.B)   { benchMut(b, "Inter", skewRev()) }
+func BenchmarkDiff_skew32_64K(b *testing.B)    { benchMut(b, "Diff", td.Skew(td.Small, td.Large)) }
+func BenchmarkDiff_skew64K_32(b *testing.B)    { benchMut(b, "Diff", skewRev()) }
+func BenchmarkIsSub_skew32_64K(b *testing.B)   { benchBool(b, "IsSub", td.Skew(td.Small, td.Large)) }
+func BenchmarkIsSuper_skew64K_32(b *testing.B) { benchBool(b, "IsSuper", skewRev()) }
+
+func BenchmarkPartition64K(b *testing.B)     { benchPartition(b, td.Overlap(2, td.Large)) }
+func BenchmarkPartition_alt64K(b *testing.B) { benchPartition(b, td.Alternate(2, td.Large)) }
+
 func BenchmarkIsInter32(b *testing.B)      { benchBool(b, "IsInter", td.Overlap(2, td.Small)) }
 func BenchmarkIsInter64K(b *testing.B)     { benchBool(b, "IsInter", td.Overlap(2, td.Large)) }
 func BenchmarkIsInter_alt32(b *testing.B)  { benchBool(b, "IsInter", td.Alternate(2, td.Small)) }
 func BenchmarkIsInter_alt64K(b *testing.B) { benchBool(b, "IsInter", td.Alternate(2, td.Large)) }
 
-func BenchmarkApply256_64K(b *testing.B) { benchApply(b, td.Rand(256, td.Large)) }
+func BenchmarkApply256_64K(b *testing.B)  { benchApply(b, applyInter, td.Rand(256, td.Large)) }
+func BenchmarkApply4096_32(b *testing.B)  { benchApply(b, applyUnion, td.Rand(4096, td.Small)) }
+func BenchmarkApplyK4096_32(b *testing.B) { benchApply(b, applyKUnion, td.Rand(4096, td.Small)) }
+
+func applyInter(data sort.Interface, pivots []int) int  { return set.Apply(set.Inter, data, pivots) }
+func applyUnion(data sort.Interface, pivots []int) int  { return set.Apply(set.Union, data, pivots) }
+func applyKUnion(data sort.Interface, pivots []int) int { return set.ApplyK(set.UnionK, data, pivots) }
+
+func skewRev() [][]int { return td.Reverse(td.Skew(td.Small, td.Large)) }
 
 func benchMut(b *testing.B, name string, sets [][]int) {
 	var op mutOp
@@ -44,6 +62,10 @@ func benchMut(b *testing.B, name string, sets [][]int) {
 	bench(b, func(a, b sliceset.Set) { op(a, b) }, sets)
 }
 
+func benchPartition(b *testing.B, sets [][]int) {
+	bench(b, func(a, b sliceset.Set) { set.Partition(append(a, b...), len(a)) }, sets)
+}
+
 func benchBool(b *testing.B, name string, sets [][]int) {
 	var op boolOp
 	td.ConvMethod(&op, sliceset.Set(nil), name)
@@ -69,7 +91,7 @@ func pivots(sets [][]int) []int {
 	return set.Pivots(lengths...)
 }
 
-func benchApply(b *testing.B, sets [][]int) {
+func benchApply(b *testing.B, apply func(sort.Interface, []int) int, sets [][]int) {
 	pivots := pivots(sets)
 	n := len(sets) - 1
 	data := make(sort.IntSlice, 0, pivots[n])
@@ -80,6 +102,6 @@ func benchApply(b *testing.B, sets [][]int) {
 		for _, set := range sets {
 			data = append(data, set...)
 		}
-		set.Apply(set.Inter, data, pivots)
+		apply(data, pivots)
 	}
 }
@@ -0,0 +1,82 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrPivotRange is returned by checked functions when pivot is not in the
+// range [0:Len].
+var ErrPivotRange = errors.New("set: pivot out of range")
+
+// ErrUnsorted is returned by checked functions when the element at Index
+// is less than the element preceding it in the same set.
+type ErrUnsorted struct{ Index int }
+
+func (e ErrUnsorted) Error() string {
+	return fmt.Sprintf("set: element %d is less than element %d", e.Index, e.Index-1)
+}
+
+// ErrDuplicate is returned by checked functions when the element at Index
+// is equal to the element preceding it in the same set.
+type ErrDuplicate struct{ Index int }
+
+func (e ErrDuplicate) Error() string {
+	return fmt.Sprintf("set: element %d is equal to element %d", e.Index, e.Index-1)
+}
+
+// Validate returns an error if pivot is not in the range [0:Len], or if
+// either of the sets [0:pivot] and [pivot:Len] is unsorted or contains
+// duplicates. The error reports the first offending index. Less is called
+// at most twice per element, and data is not modified.
+func Validate(data sort.Interface, pivot int) error {
+	n := data.Len()
+	if pivot < 0 || pivot > n {
+		return ErrPivotRange
+	}
+	if err := validate(data, 0, pivot); err != nil {
+		return err
+	}
+	return validate(data, pivot, n)
+}
+
+// validate checks that [i:j] is sorted and free of duplicates.
+func validate(data sort.Interface, i, j int) error {
+	for k := i + 1; k < j; k++ {
+		switch {
+		case data.Less(k, k-1):
+			return ErrUnsorted{k}
+		case !data.Less(k-1, k):
+			return ErrDuplicate{k}
+		}
+	}
+	return nil
+}
+
+// Check returns a function which calls Validate before calling op,
+// returning the error instead of calling op if the input is invalid. Check
+// is intended for use where data comes from sources that cannot be trusted
+// to uphold the preconditions of op.
+func Check(op Op) func(data sort.Interface, pivot int) (size int, err error) {
+	return func(data sort.Interface, pivot int) (int, error) {
+		if err := Validate(data, pivot); err != nil {
+			return 0, err
+		}
+		return op(data, pivot), nil
+	}
+}
+
+// CheckCmp is like Check, but for a Cmp.
+func CheckCmp(cmp Cmp) func(data sort.Interface, pivot int) (ok bool, err error) {
+	return func(data sort.Interface, pivot int) (bool, error) {
+		if err := Validate(data, pivot); err != nil {
+			return false, err
+		}
+		return cmp(data, pivot), nil
+	}
+}
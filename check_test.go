@@ -0,0 +1,98 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		data  []int
+		pivot int
+		err   error
+	}{
+		{nil, 0, nil},
+		{[]int{1, 2, 3, 1, 2}, 3, nil},
+		{[]int{3, 1, 2}, 0, set.ErrUnsorted{1}},
+		{[]int{1, 2, 3}, -1, set.ErrPivotRange},
+		{[]int{1, 2, 3}, 4, set.ErrPivotRange},
+		{[]int{2, 1, 3, 4}, 2, set.ErrUnsorted{1}},
+		{[]int{1, 2, 2, 3}, 3, set.ErrDuplicate{2}},
+		{[]int{1, 2, 3, 5, 4}, 3, set.ErrUnsorted{4}},
+		{[]int{1, 2, 3, 4, 4}, 3, set.ErrDuplicate{4}},
+		{[]int{3, 2, 1}, 3, set.ErrUnsorted{1}},
+	}
+
+	for _, tt := range tests {
+		data := append(sort.IntSlice(nil), tt.data...)
+		err := set.Validate(data, tt.pivot)
+
+		if err != tt.err {
+			t.Errorf("Validate(%v, %d) = %v, want %v", tt.data, tt.pivot, err, tt.err)
+		}
+		if !testdata.IsEqual(data, tt.data) {
+			t.Errorf("Validate(%v, %d) modified data to %v", tt.data, tt.pivot, data)
+		}
+
+		for name, op := range ops {
+			data := append(sort.IntSlice(nil), tt.data...)
+			_, err := set.Check(op)(data, tt.pivot)
+
+			if err != tt.err {
+				t.Errorf("Check(%s)(%v, %d) = %v, want %v", name, tt.data, tt.pivot, err, tt.err)
+			}
+			if err != nil && !testdata.IsEqual(data, tt.data) {
+				t.Errorf("Check(%s)(%v, %d) modified data to %v", name, tt.data, tt.pivot, data)
+			}
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for name, op := range ops {
+		for _, tt := range testdata.BinTests {
+			data := append(append(sort.IntSlice(nil), tt.A...), tt.B...)
+			size, err := set.Check(op)(data, len(tt.A))
+			want := tt.SelSlice(name)
+
+			if err != nil || !testdata.IsEqual(data[:size], want) {
+				t.Errorf(format, "Check("+name+")", tt.A, tt.B, data[:size], want)
+			}
+		}
+	}
+
+	for name, cmp := range cmps {
+		for _, tt := range testdata.BinTests {
+			data := append(append(sort.IntSlice(nil), tt.A...), tt.B...)
+			ok, err := set.CheckCmp(cmp)(data, len(tt.A))
+			want := tt.SelBool(name)
+
+			if err != nil || ok != want {
+				t.Errorf(format, "CheckCmp("+name+")", tt.A, tt.B, ok, want)
+			}
+		}
+	}
+}
+
+func ExampleCheck() {
+	union := set.Check(set.Union)
+
+	data := sort.IntSlice{1, 3, 5, 4, 2}
+	_, err := union(data, 3)
+
+	var e set.ErrUnsorted
+	if errors.As(err, &e) {
+		fmt.Println(err, "at index", e.Index)
+	}
+
+	// Output: set: element 4 is less than element 3 at index 4
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import "iter"
+
+// Product returns an iterator over every pair of elements from a and b,
+// in lexicographic order: all pairs with a[0] first, then a[1], and so on.
+// If a and b are sets, the pairs are sorted and unique.
+func Product[T, U any](a []T, b []U) iter.Seq2[T, U] {
+	return func(yield func(x T, y U) bool) {
+		for _, x := range a {
+			for _, y := range b {
+				if !yield(x, y) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Combinations returns an iterator over every subset of s having k
+// elements, in lexicographic order. Each subset preserves the order of s,
+// so if s is a set, so is each subset. There are no subsets if k is
+// negative or greater than len(s).
+//
+// The yielded slice is reused between iterations, so it must be copied to
+// be retained. Its capacity equals its length, so appending to it will not
+// disturb later subsets.
+func Combinations[T any](s []T, k int) iter.Seq[[]T] {
+	return func(yield func(sub []T) bool) {
+		if k < 0 || k > len(s) {
+			return
+		}
+		combinations(s, make([]int, k), make([]T, k), yield)
+	}
+}
+
+// combinations yields the subsets of s of size len(idx), using idx and
+// buf, which must be the same size, as scratch space.
+func combinations[T any](s []T, idx []int, buf []T, yield func([]T) bool) bool {
+	k, n := len(idx), len(s)
+	for i := range idx {
+		idx[i] = i
+		buf[i] = s[i]
+	}
+	for {
+		if !yield(buf) {
+			return false
+		}
+
+		// find the rightmost index that can be advanced
+		i := k - 1
+		for i >= 0 && idx[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return true
+		}
+
+		// advance it, and reset the indices following it
+		idx[i]++
+		buf[i] = s[idx[i]]
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[j-1] + 1
+			buf[j] = s[idx[j]]
+		}
+	}
+}
+
+// PowerSet returns an iterator over every subset of s, in order of size,
+// and in lexicographic order among subsets of the same size, starting with
+// the empty set and ending with s itself. Each subset preserves the order
+// of s, so if s is a set, so is each subset.
+//
+// As with Combinations, the yielded slice is reused between iterations,
+// and its capacity equals its length.
+func PowerSet[T any](s []T) iter.Seq[[]T] {
+	return func(yield func(sub []T) bool) {
+		idx, buf := make([]int, len(s)), make([]T, len(s))
+		for k := 0; k <= len(s); k++ {
+			if !combinations(s, idx[:k:k], buf[:k:k], yield) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/xtgo/set"
+)
+
+func TestProduct(t *testing.T) {
+	a, b := []int{1, 2, 3}, []string{"x", "y"}
+
+	var got []string
+	for x, y := range set.Product(a, b) {
+		got = append(got, fmt.Sprint(x, y))
+	}
+	want := []string{"1x", "1y", "2x", "2y", "3x", "3y"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Product(%v, %v) = %v, want %v", a, b, got, want)
+	}
+
+	for range set.Product(a, []int(nil)) {
+		t.Errorf("Product(%v, []) yielded a pair", a)
+	}
+}
+
+func binom(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	c := 1
+	for i := 0; i < k; i++ {
+		c = c * (n - i) / (i + 1)
+	}
+	return c
+}
+
+func TestCombinations(t *testing.T) {
+	s := []int{1, 3, 5, 7, 9, 11}
+	for k := -1; k <= len(s)+1; k++ {
+		var subs [][]int
+		for sub := range set.Combinations(s, k) {
+			if len(sub) != k || cap(sub) != k {
+				t.Fatalf("Combinations(%v, %d) yielded %v with cap %d", s, k, sub, cap(sub))
+			}
+			subs = append(subs, slices.Clone(sub))
+		}
+
+		if len(subs) != binom(len(s), k) {
+			t.Errorf("Combinations(%v, %d) yielded %d subsets, want %d", s, k, len(subs), binom(len(s), k))
+		}
+		for i, sub := range subs {
+			if !slices.IsSorted(sub) || len(slices.Compact(slices.Clone(sub))) != k {
+				t.Errorf("Combinations(%v, %d) yielded non-set %v", s, k, sub)
+			}
+			if i > 0 && slices.Compare(subs[i-1], sub) >= 0 {
+				t.Errorf("Combinations(%v, %d) yielded %v after %v", s, k, sub, subs[i-1])
+			}
+		}
+	}
+}
+
+func TestPowerSet(t *testing.T) {
+	for n := 0; n <= 8; n++ {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i * 2
+		}
+
+		seen := make(map[string]bool)
+		prev := []int(nil)
+		for sub := range set.PowerSet(s) {
+			if cap(sub) != len(sub) || !set.Chk(set.IsSub, sub, s...) {
+				t.Fatalf("PowerSet(%v) yielded %v with cap %d", s, sub, cap(sub))
+			}
+			if len(sub) < len(prev) || (len(sub) == len(prev) && seen[fmt.Sprint(sub)]) {
+				t.Errorf("PowerSet(%v) yielded %v after %v", s, sub, prev)
+			}
+			seen[fmt.Sprint(sub)] = true
+			prev = slices.Clone(sub)
+		}
+
+		if len(seen) != 1<<n {
+			t.Errorf("PowerSet(%v) yielded %d distinct subsets, want %d", s, len(seen), 1<<n)
+		}
+	}
+
+	// stopping early must not panic or yield again
+	n := 0
+	for range set.PowerSet([]int{1, 2, 3}) {
+		if n++; n == 3 {
+			break
+		}
+	}
+}
+
+func ExampleCombinations() {
+	for sub := range set.Combinations([]string{"a", "b", "c", "d"}, 2) {
+		fmt.Print(sub, " ")
+	}
+	fmt.Println()
+
+	// Output: [a b] [a c] [a d] [b c] [b d] [c d]
+}
+
+func ExamplePowerSet() {
+	s := set.Ints([]int{3, 1, 2})
+	for sub := range set.PowerSet(s) {
+		fmt.Print(sub, " ")
+	}
+	fmt.Println()
+
+	// Output: [] [1] [2] [3] [1 2] [1 3] [2 3] [1 2 3]
+}
@@ -0,0 +1,147 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// Set is a sorted, duplicate-free slice of elements. Because Set is just a
+// slice, it can be ranged over, indexed, and serialized directly; any
+// sorted, duplicate-free slice may be converted to a Set. The zero value
+// is an empty set.
+//
+// Methods that return a Set allocate a new one, leaving their receiver and
+// arguments untouched. Add and Remove modify the receiver in place, and
+// may reuse its backing array in the manner of append.
+type Set[T cmp.Ordered] []T
+
+// New returns a set containing elems, which may be in any order and may
+// contain duplicates. elems is not modified.
+func New[T cmp.Ordered](elems ...T) Set[T] {
+	return Normalize(slices.Clone(elems))
+}
+
+// FromKeys returns a set containing the keys of m.
+func FromKeys[M ~map[K]V, K cmp.Ordered, V any](m M) Set[K] {
+	s := make([]K, 0, len(m))
+	for k := range m {
+		s = append(s, k)
+	}
+	slices.Sort(s)
+	return s
+}
+
+// Len returns the number of elements in s.
+func (s Set[T]) Len() int { return len(s) }
+
+// Contains returns true if x is an element of s.
+func (s Set[T]) Contains(x T) bool { return SliceContains(s, x) }
+
+// Floor returns the greatest element of s not greater than x, if any.
+func (s Set[T]) Floor(x T) (T, bool) { return s.at(SliceFloor(s, x)) }
+
+// Ceil returns the least element of s not less than x, if any.
+func (s Set[T]) Ceil(x T) (T, bool) { return s.at(SliceCeil(s, x)) }
+
+// Pred returns the greatest element of s less than x, if any.
+func (s Set[T]) Pred(x T) (T, bool) { return s.at(SlicePred(s, x)) }
+
+// Succ returns the least element of s greater than x, if any.
+func (s Set[T]) Succ(x T) (T, bool) { return s.at(SliceSucc(s, x)) }
+
+// Range returns the subset of s in the half-open interval [lo, hi). The
+// result shares s's backing array.
+func (s Set[T]) Range(lo, hi T) Set[T] { return SliceRange(s, lo, hi) }
+
+func (s Set[T]) at(i int) (x T, ok bool) {
+	if i < 0 {
+		return x, false
+	}
+	return s[i], true
+}
+
+// All returns an iterator over the elements of s, in sorted order.
+func (s Set[T]) All() iter.Seq[T] { return slices.Values(s) }
+
+// Slice returns a copy of the elements of s as a plain slice.
+func (s Set[T]) Slice() []T { return slices.Clone(s) }
+
+// Map returns a map whose keys are the elements of s.
+func (s Set[T]) Map() map[T]struct{} {
+	m := make(map[T]struct{}, len(s))
+	for _, x := range s {
+		m[x] = struct{}{}
+	}
+	return m
+}
+
+// Add inserts elems into s, returning true if s was changed. elems may be
+// in any order and may contain duplicates.
+func (s *Set[T]) Add(elems ...T) (changed bool) {
+	*s, changed = InsertMany(*s, elems...)
+	return changed
+}
+
+// Remove deletes elems from s, returning true if s was changed. elems may
+// be in any order and may contain duplicates.
+func (s *Set[T]) Remove(elems ...T) (changed bool) {
+	*s, changed = RemoveMany(*s, elems...)
+	return changed
+}
+
+// Union returns the set of elements in either s or t.
+func (s Set[T]) Union(t Set[T]) Set[T] { return s.do(Union, t) }
+
+// Inter returns the set of elements in both s and t.
+func (s Set[T]) Inter(t Set[T]) Set[T] { return s.do(Inter, t) }
+
+// Diff returns the set of elements in s but not in t.
+func (s Set[T]) Diff(t Set[T]) Set[T] { return s.do(Diff, t) }
+
+// SymDiff returns the set of elements in exactly one of s and t.
+func (s Set[T]) SymDiff(t Set[T]) Set[T] { return s.do(SymDiff, t) }
+
+// IsSub returns true only if all elements of s are also in t.
+func (s Set[T]) IsSub(t Set[T]) bool { return s.chk(IsSub, t) }
+
+// IsSuper returns true only if all elements of t are also in s.
+func (s Set[T]) IsSuper(t Set[T]) bool { return s.chk(IsSuper, t) }
+
+// IsInter returns true if any element of s is also in t.
+func (s Set[T]) IsInter(t Set[T]) bool { return s.chk(IsInter, t) }
+
+// IsEqual returns true if s and t contain the same elements.
+func (s Set[T]) IsEqual(t Set[T]) bool { return s.chk(IsEqual, t) }
+
+func (s Set[T]) do(op Op, t Set[T]) Set[T] {
+	data := make([]T, 0, len(s)+len(t))
+	data = append(append(data, s...), t...)
+	n := op(ordered[T](data), len(s))
+	return data[:n]
+}
+
+func (s Set[T]) chk(cmp Cmp, t Set[T]) bool {
+	return Chk(cmp, s, t...)
+}
+
+// pair presents two slices as a single sort.Interface without copying.
+type pair[T any] struct {
+	s, t []T
+	less func(a, b T) bool
+}
+
+func (p pair[T]) at(i int) *T {
+	if i < len(p.s) {
+		return &p.s[i]
+	}
+	return &p.t[i-len(p.s)]
+}
+
+func (p pair[T]) Len() int           { return len(p.s) + len(p.t) }
+func (p pair[T]) Less(i, j int) bool { return p.less(*p.at(i), *p.at(j)) }
+func (p pair[T]) Swap(i, j int)      { x, y := p.at(i), p.at(j); *x, *y = *y, *x }
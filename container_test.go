@@ -0,0 +1,118 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+func TestSet(t *testing.T) {
+	type S = set.Set[int]
+
+	muts := map[string]func(a, b S) S{
+		"Union":   S.Union,
+		"Inter":   S.Inter,
+		"Diff":    S.Diff,
+		"SymDiff": S.SymDiff,
+	}
+	bools := map[string]func(a, b S) bool{
+		"IsSub":   S.IsSub,
+		"IsSuper": S.IsSuper,
+		"IsInter": S.IsInter,
+		"IsEqual": S.IsEqual,
+	}
+
+	for _, tt := range testdata.BinTests {
+		a, b := S(tt.A), S(tt.B)
+
+		for name, op := range muts {
+			c := op(a, b)
+			want := tt.SelSlice(name)
+
+			if !testdata.IsEqual(c, want) {
+				t.Errorf(format, name, tt.A, tt.B, c, want)
+			}
+		}
+
+		for name, op := range bools {
+			ok := op(a, b)
+			want := tt.SelBool(name)
+
+			if ok != want {
+				t.Errorf(format, name, tt.A, tt.B, ok, want)
+			}
+		}
+
+		if !testdata.IsEqual(a, tt.A) || !testdata.IsEqual(b, tt.B) {
+			t.Errorf("operands modified: got %v, %v, want %v, %v", a, b, tt.A, tt.B)
+		}
+	}
+}
+
+func TestSetAddRemove(t *testing.T) {
+	s := set.New(5, 1, 3, 1)
+
+	if !s.Add(4, 2, 4) || s.Add(1, 5) {
+		t.Error("Add reported the wrong change status")
+	}
+	if want := []int{1, 2, 3, 4, 5}; !testdata.IsEqual(s, want) {
+		t.Errorf("after Add: got %v, want %v", s, want)
+	}
+
+	if !s.Remove(2, 6) || s.Remove(6) {
+		t.Error("Remove reported the wrong change status")
+	}
+	if want := []int{1, 3, 4, 5}; !testdata.IsEqual(s, want) {
+		t.Errorf("after Remove: got %v, want %v", s, want)
+	}
+
+	if !s.Contains(4) || s.Contains(2) {
+		t.Errorf("Contains gave wrong results for %v", s)
+	}
+
+	u := set.FromKeys(s.Map())
+	if !u.IsEqual(s) {
+		t.Errorf("FromKeys(%v.Map()) = %v", s, u)
+	}
+}
+
+func TestSetLookup(t *testing.T) {
+	s := set.New(10, 20, 30)
+
+	if x, ok := s.Floor(25); x != 20 || !ok {
+		t.Errorf("Floor(25) = %d, %t, want 20, true", x, ok)
+	}
+	if x, ok := s.Succ(30); ok {
+		t.Errorf("Succ(30) = %d, %t, want 0, false", x, ok)
+	}
+	if r := s.Range(15, 30); !testdata.IsEqual(r, []int{20}) {
+		t.Errorf("Range(15, 30) = %v, want [20]", r)
+	}
+}
+
+func ExampleSet() {
+	s := set.New("gamma", "alpha", "gamma")
+	s.Add("beta")
+
+	t := set.New("beta", "delta")
+
+	fmt.Println("union:", s.Union(t))
+	fmt.Println("inter:", s.Inter(t))
+
+	for x := range s.All() {
+		fmt.Println(x)
+	}
+
+	// Output:
+	// union: [alpha beta delta gamma]
+	// inter: [beta]
+	// alpha
+	// beta
+	// gamma
+}
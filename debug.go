@@ -0,0 +1,11 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build setdebug
+
+package set
+
+// debug enables the assertions in assert.go, which panic when the
+// preconditions or postconditions of an operation do not hold.
+const debug = true
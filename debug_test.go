@@ -0,0 +1,52 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build setdebug
+
+package set_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/xtgo/set"
+)
+
+// noSwap is a sort.Interface whose Swap does nothing.
+type noSwap []int
+
+func (s noSwap) Len() int           { return len(s) }
+func (s noSwap) Less(i, j int) bool { return s[i] < s[j] }
+func (s noSwap) Swap(i, j int)      {}
+
+func TestDebugAssertions(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+		want string
+	}{
+		{"pivot", func() { set.Union(sort.IntSlice{1, 2}, 3) }, "Union: invalid input: pivot 3 out of range [0:2]"},
+		{"unsorted", func() { set.Inter(sort.IntSlice{1, 2, 3, 2, 1}, 3) }, "Inter: invalid input: element 4 is less than element 3"},
+		{"duplicate", func() { set.Diff(sort.IntSlice{1, 1, 2}, 2) }, "Diff: invalid input: element 1 is equal to element 0"},
+		{"cmp", func() { set.IsSub(sort.IntSlice{2, 1}, 2) }, "IsSub: invalid input: element 1 is less than element 0"},
+		{"uniq", func() { set.Uniq(sort.IntSlice{2, 1}) }, "Uniq: invalid input: element 1 is less than element 0"},
+		{"output", func() { set.SymDiff(noSwap{2, 3, 1}, 2) }, "SymDiff: invalid output: element 2 is less than element 1"},
+		{"pivots", func() { set.Apply(set.Union, sort.IntSlice{1, 2, 3}, []int{2, 1, 3}) }, "Apply: invalid input: pivots[1] = 1 out of range [2:3]"},
+		{"apply", func() { set.Apply(set.Union, sort.IntSlice{1, 2, 2, 1}, []int{2, 4}) }, "Apply: invalid input: set 1: element 3 is less than element 2"},
+	}
+
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				msg := fmt.Sprint(recover())
+				if !strings.HasSuffix(msg, tt.want) {
+					t.Errorf("%s: panic %q, want %q", tt.name, msg, tt.want)
+				}
+			}()
+			tt.fn()
+		}()
+	}
+}
@@ -0,0 +1,369 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package delta implements a compact binary encoding for sorted,
+// duplicate-free int sets, such as those produced by set.Ints.
+//
+// A stream is a sequence of blocks, each holding up to a fixed number of
+// elements, followed by an empty block. Each block begins with a header of
+// four varints: the number of elements, n (non-zero, except in the final
+// empty block); the length, in bytes, of the block's payload; the first
+// element (zig-zag encoded, so it may be negative); and the difference
+// between the last and first elements. The payload holds the n-1 gaps
+// between consecutive elements as unsigned varints.
+//
+// Since each header records the range of values in its block, a Decoder
+// can skip whole blocks without decoding them (see SkipTo), which lets
+// Inter avoid decoding most of a large stream when intersecting it with a
+// small one.
+package delta
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"iter"
+)
+
+const (
+	// DefaultBlockSize is the block size used when zero is passed to
+	// NewEncoder.
+	DefaultBlockSize = 128
+
+	// MaxBlockSize is the largest number of elements in a block. Decoders
+	// reject streams with larger blocks.
+	MaxBlockSize = 1 << 16
+)
+
+var (
+	// ErrNotIncreasing is returned by Encoder.Write when an element is not
+	// greater than the element preceding it.
+	ErrNotIncreasing = errors.New("delta: elements not strictly increasing")
+
+	// ErrCorrupt is reported by a Decoder when the stream is malformed,
+	// including when its elements are not strictly increasing.
+	ErrCorrupt = errors.New("delta: corrupt stream")
+)
+
+// An Encoder writes an encoded set to an underlying io.Writer.
+type Encoder struct {
+	w     io.Writer
+	size  int
+	block []int // elements of the block being accumulated
+	last  int   // the last element written
+	any   bool  // whether any element has been written
+	buf   []byte
+	err   error
+}
+
+// NewEncoder returns an Encoder writing to w in blocks of blockSize
+// elements, or DefaultBlockSize if blockSize is not positive. Larger block
+// sizes give slightly smaller output, and smaller ones allow SkipTo to skip
+// more precisely. Close must be called to complete the stream.
+func NewEncoder(w io.Writer, blockSize int) *Encoder {
+	switch {
+	case blockSize <= 0:
+		blockSize = DefaultBlockSize
+	case blockSize > MaxBlockSize:
+		blockSize = MaxBlockSize
+	}
+	return &Encoder{w: w, size: blockSize, block: make([]int, 0, blockSize)}
+}
+
+// Write encodes the elements of s, which must be strictly increasing and
+// greater than any element previously written. Once an error has occurred,
+// it is returned by every subsequent call.
+func (e *Encoder) Write(s ...int) error {
+	for _, x := range s {
+		if e.err != nil {
+			return e.err
+		}
+		if e.any && x <= e.last {
+			e.err = ErrNotIncreasing
+			return e.err
+		}
+		e.last, e.any = x, true
+		if e.block = append(e.block, x); len(e.block) == e.size {
+			e.flush()
+		}
+	}
+	return e.err
+}
+
+// Close writes any buffered elements and the end of the stream. It does
+// not close the underlying io.Writer.
+func (e *Encoder) Close() error {
+	if len(e.block) > 0 {
+		e.flush()
+	}
+	if e.err == nil {
+		_, e.err = e.w.Write([]byte{0, 0, 0, 0})
+	}
+	return e.err
+}
+
+// flush writes the accumulated block.
+func (e *Encoder) flush() {
+	if e.err != nil {
+		return
+	}
+	s := e.block
+	first, last := s[0], s[len(s)-1]
+
+	// encode the payload after room for the largest possible header
+	const maxHeader = 4 * binary.MaxVarintLen64
+	p := append(e.buf[:0], make([]byte, maxHeader)...)
+	for i := 1; i < len(s); i++ {
+		p = binary.AppendUvarint(p, uint64(s[i])-uint64(s[i-1]))
+	}
+	payload := p[maxHeader:]
+
+	h := binary.AppendUvarint(p[:0], uint64(len(s)))
+	h = binary.AppendUvarint(h, uint64(len(payload)))
+	h = binary.AppendVarint(h, int64(first))
+	h = binary.AppendUvarint(h, uint64(last)-uint64(first))
+
+	// close the gap between the header and payload
+	n := copy(p[len(h):], payload)
+	_, e.err = e.w.Write(p[:len(h)+n])
+
+	e.buf = p
+	e.block = e.block[:0]
+}
+
+// Encode writes the set s to w as a complete stream.
+func Encode(w io.Writer, s []int) error {
+	e := NewEncoder(w, 0)
+	e.Write(s...)
+	return e.Close()
+}
+
+// A Decoder reads an encoded set from an underlying io.Reader, one element
+// at a time, validating the stream as it goes.
+type Decoder struct {
+	r    reader
+	buf  []byte // undecoded gaps of the current block
+	skip int    // unread payload bytes of the current block
+	n    int    // elements remaining in the current block
+	x    int    // the next element in the current block
+	last int    // the last element of the current block
+	prev int    // the last element of the previous block
+	any  bool   // whether any block has been read
+	err  error
+	done bool
+}
+
+type reader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// NewDecoder returns a Decoder reading from r. If r does not implement
+// io.ByteReader, it is buffered, and the Decoder may read past the end of
+// the stream.
+func NewDecoder(r io.Reader) *Decoder {
+	br, ok := r.(reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Decoder{r: br}
+}
+
+// Err returns the first error encountered, if any.
+func (d *Decoder) Err() error { return d.err }
+
+// Next returns the next element of the set, or false once the set has
+// been exhausted or an error has occurred.
+func (d *Decoder) Next() (x int, ok bool) {
+	if d.n == 0 && !d.readBlock(false) {
+		return 0, false
+	}
+	x = d.x
+	if d.n--; d.n > 0 {
+		gap, k := binary.Uvarint(d.buf)
+		if k <= 0 || gap == 0 || gap > uint64(d.last)-uint64(x) {
+			return 0, d.fail(ErrCorrupt)
+		}
+		d.buf = d.buf[k:]
+		d.x = x + int(gap)
+	} else if len(d.buf) != 0 || x != d.last {
+		return 0, d.fail(ErrCorrupt)
+	}
+	return x, true
+}
+
+// SkipTo returns the least remaining element not less than x, consuming
+// it along with the elements before it. Blocks lying entirely before x are
+// discarded without being decoded.
+func (d *Decoder) SkipTo(x int) (y int, ok bool) {
+	for d.n == 0 || d.last < x {
+		d.n = 0
+		if !d.readBlock(true) {
+			return 0, false
+		}
+		if d.last >= x {
+			d.readPayload()
+		}
+	}
+	for {
+		y, ok = d.Next()
+		if !ok || y >= x {
+			return y, ok
+		}
+	}
+}
+
+// All returns an iterator over the remaining elements of the set. Err
+// should be checked once iteration has finished.
+func (d *Decoder) All() iter.Seq[int] {
+	return func(yield func(x int) bool) {
+		for {
+			x, ok := d.Next()
+			if !ok || !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// readBlock reads the next block header. If lazy is true, the payload is
+// left unread, to be read or skipped by the caller.
+func (d *Decoder) readBlock(lazy bool) bool {
+	if d.done || d.err != nil {
+		return false
+	}
+	if d.skip > 0 {
+		_, err := io.CopyN(io.Discard, d.r, int64(d.skip))
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if d.skip = 0; err != nil {
+			return d.fail(err)
+		}
+	}
+	var h [4]uint64
+	for i := range h {
+		var err error
+		if i == 2 {
+			var v int64
+			v, err = binary.ReadVarint(d.r)
+			h[i] = uint64(v)
+		} else {
+			h[i], err = binary.ReadUvarint(d.r)
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return d.fail(err)
+		}
+	}
+
+	n, size, first, span := h[0], h[1], int(h[2]), h[3]
+	switch {
+	case n == 0:
+		if size != 0 || first != 0 || span != 0 {
+			return d.fail(ErrCorrupt)
+		}
+		d.done = true
+		return false
+	case n > MaxBlockSize || size > (n-1)*binary.MaxVarintLen64 || span < n-1 || (n == 1) != (span == 0):
+		return d.fail(ErrCorrupt)
+	case d.any && first <= d.prev:
+		return d.fail(ErrCorrupt)
+	}
+	d.last = int(uint64(first) + span)
+	if d.last < first {
+		return d.fail(ErrCorrupt)
+	}
+
+	d.n, d.x, d.prev, d.any = int(n), first, d.last, true
+	d.skip = int(size)
+	if !lazy {
+		return d.readPayload()
+	}
+	return true
+}
+
+// readPayload reads the payload of the current block into buf. If it is
+// not called, the payload is skipped by the next call to readBlock.
+func (d *Decoder) readPayload() bool {
+	d.buf = append(d.buf[:0], make([]byte, d.skip)...)
+	d.skip = 0
+	if _, err := io.ReadFull(d.r, d.buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return d.fail(err)
+	}
+	return true
+}
+
+func (d *Decoder) fail(err error) bool {
+	if d.err == nil {
+		d.err = err
+	}
+	d.n = 0
+	return false
+}
+
+// Decode reads a complete stream from r, returning the set it encodes.
+func Decode(r io.Reader) ([]int, error) {
+	d := NewDecoder(r)
+	var s []int
+	for x := range d.All() {
+		s = append(s, x)
+	}
+	return s, d.Err()
+}
+
+// Inter appends to dst the elements common to the sets read by a and b,
+// returning the extended slice. Neither set is materialized, and each
+// Decoder skips over blocks that cannot intersect the other set.
+func Inter(dst []int, a, b *Decoder) ([]int, error) {
+	x, aok := a.Next()
+	y, bok := b.Next()
+	for aok && bok {
+		switch {
+		case x < y:
+			x, aok = a.SkipTo(y)
+		case y < x:
+			y, bok = b.SkipTo(x)
+		default:
+			dst = append(dst, x)
+			x, aok = a.Next()
+			y, bok = b.Next()
+		}
+	}
+	return dst, errors.Join(a.Err(), b.Err())
+}
+
+// Union appends to dst the elements in either of the sets read by a and b,
+// returning the extended slice. Neither set is materialized beyond dst.
+func Union(dst []int, a, b *Decoder) ([]int, error) {
+	x, aok := a.Next()
+	y, bok := b.Next()
+	for aok && bok {
+		switch {
+		case x < y:
+			dst = append(dst, x)
+			x, aok = a.Next()
+		case y < x:
+			dst = append(dst, y)
+			y, bok = b.Next()
+		default:
+			dst = append(dst, x)
+			x, aok = a.Next()
+			y, bok = b.Next()
+		}
+	}
+	for ; aok; x, aok = a.Next() {
+		dst = append(dst, x)
+	}
+	for ; bok; y, bok = b.Next() {
+		dst = append(dst, y)
+	}
+	return dst, errors.Join(a.Err(), b.Err())
+}
@@ -0,0 +1,175 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package delta_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/delta"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+func encode(t *testing.T, s []int, blockSize int) []byte {
+	var buf bytes.Buffer
+	e := delta.NewEncoder(&buf, blockSize)
+	if err := e.Write(s...); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func randInts(rng *rand.Rand, n, max int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = rng.Intn(max) - max/2
+	}
+	return set.Ints(s)
+}
+
+func TestRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	tests := [][]int{
+		nil,
+		{0},
+		{-1},
+		{math.MinInt, 0, math.MaxInt},
+		testdata.Seq(0, 1000, 1),
+		testdata.Seq(-5000, 5000, 7),
+		randInts(rng, 1000, math.MaxInt32),
+	}
+	for _, s := range tests {
+		for _, size := range []int{0, 1, 3, 128} {
+			b := encode(t, s, size)
+			got, err := delta.Decode(bytes.NewReader(b))
+			if err != nil || !testdata.IsEqual(got, s) {
+				t.Errorf("Decode(Encode(%v), %d) = %v, %v", s, size, got, err)
+			}
+		}
+	}
+}
+
+func TestEncoderOrder(t *testing.T) {
+	for _, s := range [][]int{{1, 1}, {2, 1}, {1, 3, 2}} {
+		var buf bytes.Buffer
+		e := delta.NewEncoder(&buf, 0)
+		if err := e.Write(s...); err != delta.ErrNotIncreasing {
+			t.Errorf("Write(%v) = %v, want %v", s, err, delta.ErrNotIncreasing)
+		}
+		if err := e.Close(); err != delta.ErrNotIncreasing {
+			t.Errorf("Close() after Write(%v) = %v, want %v", s, err, delta.ErrNotIncreasing)
+		}
+	}
+}
+
+// block returns a hand-built block with the given header and gaps.
+func block(n, size uint64, first int64, span uint64, gaps ...uint64) []byte {
+	b := binary.AppendUvarint(nil, n)
+	b = binary.AppendUvarint(b, size)
+	b = binary.AppendVarint(b, first)
+	b = binary.AppendUvarint(b, span)
+	for _, g := range gaps {
+		b = binary.AppendUvarint(b, g)
+	}
+	return b
+}
+
+func TestDecodeCorrupt(t *testing.T) {
+	end := []byte{0, 0, 0, 0}
+	cat := func(bs ...[]byte) []byte { return bytes.Join(bs, nil) }
+
+	tests := []struct {
+		name string
+		in   []byte
+		err  error
+	}{
+		{"zero gap", cat(block(3, 2, 5, 1, 1, 0), end), delta.ErrCorrupt},
+		{"span mismatch", cat(block(3, 2, 5, 9, 1, 1), end), delta.ErrCorrupt},
+		{"overrun", cat(block(3, 2, 5, 2, 1, 5), end), delta.ErrCorrupt},
+		{"size mismatch", cat(block(2, 2, 5, 1, 1, 0), end), delta.ErrCorrupt},
+		{"single with span", cat(block(1, 0, 5, 1), end), delta.ErrCorrupt},
+		{"blocks overlap", cat(block(1, 0, 5, 0), block(1, 0, 5, 0), end), delta.ErrCorrupt},
+		{"blocks unordered", cat(block(1, 0, 5, 0), block(1, 0, 4, 0), end), delta.ErrCorrupt},
+		{"bad terminator", cat(block(1, 0, 5, 0), []byte{0, 1, 0, 0}), delta.ErrCorrupt},
+		{"huge block", cat(block(1<<40, 10, 0, 1<<41), end), delta.ErrCorrupt},
+		{"truncated header", block(3, 2, 5, 2)[:2], io.ErrUnexpectedEOF},
+		{"truncated payload", block(3, 2, 5, 2, 1), io.ErrUnexpectedEOF},
+		{"missing terminator", block(1, 0, 5, 0), io.ErrUnexpectedEOF},
+		{"empty", nil, io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		_, err := delta.Decode(bytes.NewReader(tt.in))
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: Decode(%v) error = %v, want %v", tt.name, tt.in, err, tt.err)
+		}
+	}
+}
+
+func TestSkipTo(t *testing.T) {
+	s := testdata.Seq(0, 3000, 3)
+	b := encode(t, s, 16)
+
+	d := delta.NewDecoder(bytes.NewReader(b))
+	for _, tt := range []struct{ x, want int }{
+		{-10, 0}, {1, 3}, {100, 102}, {100, 105}, {2000, 2001}, {2997, 2997},
+	} {
+		if got, ok := d.SkipTo(tt.x); !ok || got != tt.want {
+			t.Errorf("SkipTo(%d) = %d, %t; want %d", tt.x, got, ok, tt.want)
+		}
+	}
+	if x, ok := d.SkipTo(2998); ok {
+		t.Errorf("SkipTo(2998) = %d, want end of stream", x)
+	}
+	if err := d.Err(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestInterUnion(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 50; i++ {
+		a := randInts(rng, rng.Intn(2000), 5000)
+		b := randInts(rng, rng.Intn(50), 5000)
+		if i%2 == 0 {
+			a, b = b, a
+		}
+		ab, bb := encode(t, a, 8), encode(t, b, 8)
+
+		dec := func(b []byte) *delta.Decoder { return delta.NewDecoder(bytes.NewReader(b)) }
+
+		got, err := delta.Inter(nil, dec(ab), dec(bb))
+		want := set.Do(set.Inter, append([]int(nil), a...), b...)
+		if err != nil || !testdata.IsEqual(got, want) {
+			t.Errorf("Inter(%v, %v) = %v, %v; want %v", a, b, got, err, want)
+		}
+
+		got, err = delta.Union(nil, dec(ab), dec(bb))
+		want = set.Do(set.Union, append([]int(nil), a...), b...)
+		if err != nil || !testdata.IsEqual(got, want) {
+			t.Errorf("Union(%v, %v) = %v, %v; want %v", a, b, got, err, want)
+		}
+	}
+}
+
+func ExampleInter() {
+	var a, b bytes.Buffer
+	delta.Encode(&a, []int{1, 3, 5, 7, 9})
+	delta.Encode(&b, []int{3, 4, 5, 6})
+
+	s, err := delta.Inter(nil, delta.NewDecoder(&a), delta.NewDecoder(&b))
+	fmt.Println(s, err)
+
+	// Output: [3 5] <nil>
+}
@@ -17,15 +17,36 @@
 // duplicates. Elements which were moved into the range [size:Len] will have
 // undefined order and may contain duplicates.
 //
+// The Bag ops, such as BagInter, instead treat the two sorted ranges as
+// multisets (bags), in which duplicate elements are meaningful: the number
+// of copies of each element in their sorted output, [0:size], is derived
+// from the number of copies in each input.
+//
+// The Len functions, such as InterLen, report the size an op would return
+// without modifying data, and underlie the similarity measures, such as
+// Jaccard. ApplyLen does the same for ApplyK.
+//
 // All pivots must be in the range [0:Len]. A panic may occur when invalid
-// pivots are passed into any of the functions.
+// pivots are passed into any of the functions. Where input cannot be
+// trusted, Check and CheckCmp wrap an Op or Cmp so that invalid pivots,
+// unsorted sets, and duplicates are reported as errors instead. Building
+// with the setdebug tag makes the mutating and comparison functions, as
+// well as Uniq and Apply, assert their preconditions and postconditions,
+// panicking with the offending indices; without the tag, the assertions
+// are compiled away.
 //
 // Convenience functions exist for slices of int, float64, and string
 // element types, and also serve as examples for implementing utility
-// functions for other types.
+// functions for other types. Do, Chk, and Normalize accept slices of any
+// cmp.Ordered element type, and their Func variants accept any element type
+// along with a comparison function, so most element types need no
+// sort.Interface implementation at all.
 //
 // Elements will be considered equal if `!Less(i,j) && !Less(j,i)`. An
 // implication of this is that NaN values are equal to each other.
+//
+// Ops that walk both sets in step use galloping (exponential) search to
+// skip over runs of elements, so when one set is much smaller than the
+// other, the number of Less calls is O(m*log(n/m)) rather than O(m+n),
+// where m and n are the sizes of the smaller and larger sets.
 package set
-
-// BUG(extemporalgenome): All ops should use binary search when runs are detected
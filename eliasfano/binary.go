@@ -0,0 +1,214 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eliasfano
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"unsafe"
+)
+
+// The binary encoding of a Set is a sequence of little-endian 64-bit
+// words: a header of nine words (the magic number, the number of elements,
+// the least and greatest elements, the number of low bits per element, and
+// the lengths, in words, of the four sections that follow), then the
+// packed low bits, the high bit vector, and the sampled positions of ones
+// and zeros in the high bit vector. Being made up of whole words, the
+// encoding can be viewed in place by FromBytes.
+
+// magic identifies the encoding, and its version.
+const magic = "EFSET\x00\x00\x01"
+
+const headerWords = 9
+
+// ErrFormat is returned when decoding data that is not a valid encoding of
+// a Set.
+var ErrFormat = errors.New("eliasfano: invalid encoding")
+
+// AppendBinary appends the binary encoding of e to b, returning the
+// extended slice.
+func (e *Set) AppendBinary(b []byte) ([]byte, error) {
+	le := binary.LittleEndian
+	b = append(b, magic...)
+	for _, v := range []uint64{
+		uint64(e.n), uint64(e.base), uint64(e.last), uint64(e.l),
+		uint64(len(e.low)), uint64(len(e.high)), uint64(len(e.ones)), uint64(len(e.zeros)),
+	} {
+		b = le.AppendUint64(b, v)
+	}
+	for _, sec := range [][]uint64{e.low, e.high, e.ones, e.zeros} {
+		for _, v := range sec {
+			b = le.AppendUint64(b, v)
+		}
+	}
+	return b, nil
+}
+
+// MarshalBinary returns the binary encoding of e.
+func (e *Set) MarshalBinary() ([]byte, error) {
+	return e.AppendBinary(make([]byte, 0, 8*(headerWords+len(e.low)+len(e.high)+len(e.ones)+len(e.zeros))))
+}
+
+// UnmarshalBinary sets e to the Set encoded in b, which is copied.
+func (e *Set) UnmarshalBinary(b []byte) error {
+	words, err := decode(b)
+	if err != nil {
+		return err
+	}
+	d, err := parse(words)
+	if err != nil {
+		return err
+	}
+	*e = *d
+	return nil
+}
+
+// FromBytes returns a Set backed by b, which holds an encoding produced by
+// AppendBinary or MarshalBinary, such as a memory-mapped file. If b is
+// aligned to 8 bytes and the machine is little-endian, b is used in place,
+// and must not be modified while the Set is in use; otherwise, b is
+// copied. FromBytes checks the header against the sizes of the sections,
+// and the high bit vector against its samples, in a single pass that
+// copies nothing; the packed low bits are not checked.
+func FromBytes(b []byte) (*Set, error) {
+	if len(b)%8 != 0 {
+		return nil, ErrFormat
+	}
+	if len(b) == 0 || !littleEndian || uintptr(unsafe.Pointer(unsafe.SliceData(b)))%8 != 0 {
+		words, err := decode(b)
+		if err != nil {
+			return nil, err
+		}
+		return parse(words)
+	}
+	return parse(unsafe.Slice((*uint64)(unsafe.Pointer(unsafe.SliceData(b))), len(b)/8))
+}
+
+var littleEndian = binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+
+// decode copies b into a new slice of words.
+func decode(b []byte) ([]uint64, error) {
+	if len(b)%8 != 0 {
+		return nil, ErrFormat
+	}
+	words := make([]uint64, len(b)/8)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	return words, nil
+}
+
+// parse returns a Set whose sections are slices of words, after checking
+// that the header is consistent with the size of words and that the high
+// bit vector is consistent with the header and samples.
+func parse(words []uint64) (*Set, error) {
+	if len(words) < headerWords || words[0] != binary.LittleEndian.Uint64([]byte(magic)) {
+		return nil, ErrFormat
+	}
+	h := words[1:headerWords]
+	n, base, last, l := h[0], int(h[1]), int(h[2]), h[3]
+	sizes := h[4:]
+	if uint64(base) != h[1] || uint64(last) != h[2] {
+		return nil, ErrFormat // out of range for int
+	}
+
+	// n is bounded by the number of bits in high
+	total := uint64(len(words) - headerWords)
+	if n > total*64 || l >= 64 {
+		return nil, ErrFormat
+	}
+	if n == 0 {
+		if base != 0 || last != 0 || l != 0 || total != 0 {
+			return nil, ErrFormat
+		}
+		return &Set{}, nil
+	}
+	if last < base {
+		return nil, ErrFormat
+	}
+	buckets := (uint64(last) - uint64(base)) >> l
+	if buckets > total*64 {
+		return nil, ErrFormat
+	}
+	want := []uint64{
+		(n*l + 63) / 64,
+		(n + buckets + 1 + 63) / 64,
+		(n + sample - 1) / sample,
+		(buckets + 1 + sample - 1) / sample,
+	}
+	sum := uint64(0)
+	for i, m := range want {
+		if sizes[i] != m {
+			return nil, ErrFormat
+		}
+		sum += m
+	}
+	if sum != total {
+		return nil, ErrFormat
+	}
+
+	e := &Set{n: int(n), base: base, last: last, l: uint(l)}
+	secs := []*[]uint64{&e.low, &e.high, &e.ones, &e.zeros}
+	p := headerWords
+	for i, sec := range secs {
+		q := p + int(want[i])
+		*sec = words[p:q:q]
+		p = q
+	}
+
+	// the greatest element must set the last bit in high
+	top := n + buckets - 1
+	w := int(top / 64)
+	if e.high[w]>>(top%64) != 1 {
+		return nil, ErrFormat
+	}
+	for _, v := range e.high[w+1:] {
+		if v != 0 {
+			return nil, ErrFormat
+		}
+	}
+	if !e.checkSamples(n + buckets + 1) {
+		return nil, ErrFormat
+	}
+	return e, nil
+}
+
+// checkSamples reports whether the first nbits bits of high hold n ones,
+// and ones and zeros hold the positions of every sample'th one and zero.
+func (e *Set) checkSamples(nbits uint64) bool {
+	var ones, zeros uint64 // counted before the current word
+	for w, word := range e.high {
+		width := min(64, nbits-uint64(w)*64)
+		if !checkWord(e.ones, ones, w, word) || !checkWord(e.zeros, zeros, w, ^word&(1<<width-1)) {
+			return false
+		}
+		c := uint64(bits.OnesCount64(word))
+		ones += c
+		zeros += width - c
+	}
+	return ones == uint64(e.n)
+}
+
+// checkWord reports whether samples holds the position of every one in the
+// w'th word of the bit vector, set to word, whose rank is a multiple of
+// sample, given that the preceding words hold count ones.
+func checkWord(samples []uint64, count uint64, w int, word uint64) bool {
+	k := (count + sample - 1) / sample * sample
+	for ; k < count+uint64(bits.OnesCount64(word)); k += sample {
+		if k/sample >= uint64(len(samples)) || samples[k/sample] != uint64(w)*64+selectWord(word, k-count) {
+			return false
+		}
+	}
+	return true
+}
+
+// selectWord returns the position of the k'th one in word.
+func selectWord(word, k uint64) uint64 {
+	for ; k > 0; k-- {
+		word &= word - 1
+	}
+	return uint64(bits.TrailingZeros64(word))
+}
@@ -0,0 +1,350 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package eliasfano implements a read-only set of ints using the succinct
+// Elias-Fano encoding, suited to large, static sets such as posting lists.
+//
+// Each element is stored relative to the least element, and split into a
+// fixed number of low bits, which are packed into an array, and the
+// remaining high bits, which are stored in unary in a bit vector. A set of
+// n elements spanning a range of u values takes fewer than 2+log2(u/n) bits
+// per element. Sampled positions within the bit vector make Select and
+// NextGEQ run in roughly constant time, and all queries, including
+// iteration, run without decompressing the set.
+//
+// FromInts builds a Set from the sorted, duplicate-free []int sets that
+// package set produces. A Set can be written out with MarshalBinary, and
+// FromBytes reads it back without copying, so large sets can be stored in
+// files and memory-mapped.
+package eliasfano
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+	"sort"
+)
+
+// sample is the number of ones (and zeros) in the high bit vector between
+// each sampled position.
+const sample = 256
+
+// Set is an immutable set of ints. The zero value is an empty set.
+type Set struct {
+	n     int      // number of elements
+	base  int      // the least element
+	last  int      // the greatest element
+	l     uint     // number of low bits per element
+	low   []uint64 // packed low bits, l per element
+	high  []uint64 // element i, with high bits h, sets bit h+i
+	ones  []uint64 // position of every sample'th one in high
+	zeros []uint64 // position of every sample'th zero in high
+}
+
+// FromInts returns a Set containing the elements of s. FromInts panics if
+// s is not sorted and free of duplicates.
+func FromInts(s []int) *Set {
+	e := &Set{n: len(s)}
+	if len(s) == 0 {
+		return e
+	}
+	e.base, e.last = s[0], s[len(s)-1]
+	for i := 1; i < len(s); i++ {
+		if s[i] <= s[i-1] {
+			panic(fmt.Sprintf("eliasfano: elements %d and %d out of order", i-1, i))
+		}
+	}
+
+	u := uint64(s[len(s)-1]) - uint64(e.base)
+	if q := u / uint64(len(s)); q > 0 {
+		e.l = uint(bits.Len64(q) - 1)
+	}
+
+	nhigh := uint64(len(s)) + u>>e.l + 1
+	e.high = make([]uint64, (nhigh+63)/64)
+	e.low = make([]uint64, (uint64(len(s))*uint64(e.l)+63)/64)
+	mask := uint64(1)<<e.l - 1
+
+	for i, x := range s {
+		v := uint64(x) - uint64(e.base)
+		e.setLow(i, v&mask)
+		p := v>>e.l + uint64(i)
+		e.high[p/64] |= 1 << (p % 64)
+	}
+
+	// sample the positions of ones and zeros
+	var ones, zeros int
+	for p := uint64(0); p < nhigh; p++ {
+		if e.high[p/64]&(1<<(p%64)) != 0 {
+			if ones%sample == 0 {
+				e.ones = append(e.ones, p)
+			}
+			ones++
+		} else {
+			if zeros%sample == 0 {
+				e.zeros = append(e.zeros, p)
+			}
+			zeros++
+		}
+	}
+	return e
+}
+
+func (e *Set) setLow(i int, v uint64) {
+	if e.l == 0 {
+		return
+	}
+	p := uint64(i) * uint64(e.l)
+	w, off := p/64, p%64
+	e.low[w] |= v << off
+	if off+uint64(e.l) > 64 {
+		e.low[w+1] |= v >> (64 - off)
+	}
+}
+
+func (e *Set) getLow(i int) uint64 {
+	if e.l == 0 {
+		return 0
+	}
+	p := uint64(i) * uint64(e.l)
+	w, off := p/64, p%64
+	v := e.low[w] >> off
+	if off+uint64(e.l) > 64 {
+		v |= e.low[w+1] << (64 - off)
+	}
+	return v & (1<<e.l - 1)
+}
+
+// value returns the element at index i, whose one is at position p.
+func (e *Set) value(i, p int) int {
+	return int(uint64(e.base) + (uint64(p-i)<<e.l | e.getLow(i)))
+}
+
+// selectBit returns the position of the k'th one in high, or of the k'th
+// zero if zero is true.
+func (e *Set) selectBit(k int, zero bool) int {
+	samples, flip := e.ones, uint64(0)
+	if zero {
+		samples, flip = e.zeros, ^uint64(0)
+	}
+	p := int(samples[k/sample])
+	k %= sample
+	w := p / 64
+	word := (e.high[w] ^ flip) &^ (1<<(p%64) - 1)
+	for {
+		if c := bits.OnesCount64(word); k >= c {
+			k -= c
+			w++
+			word = e.high[w] ^ flip
+			continue
+		}
+		for ; k > 0; k-- {
+			word &= word - 1
+		}
+		return w*64 + bits.TrailingZeros64(word)
+	}
+}
+
+// nextOne returns the position of the first one in high after p.
+func (e *Set) nextOne(p int) int {
+	p++
+	w := p / 64
+	word := e.high[w] &^ (1<<(p%64) - 1)
+	for word == 0 {
+		w++
+		word = e.high[w]
+	}
+	return w*64 + bits.TrailingZeros64(word)
+}
+
+// Len returns the number of elements in e.
+func (e *Set) Len() int { return e.n }
+
+// Select returns the element at index i, where elements are indexed in
+// ascending order from zero. Select panics if i is not in [0:Len].
+func (e *Set) Select(i int) int {
+	if i < 0 || i >= e.n {
+		panic(fmt.Sprintf("eliasfano: index %d out of range [0:%d]", i, e.n))
+	}
+	return e.value(i, e.selectBit(i, false))
+}
+
+// NextGEQ returns the index and value of the least element not less than
+// x. If there is no such element, i is Len.
+func (e *Set) NextGEQ(x int) (i, v int) {
+	c := e.cursor()
+	c.skipTo(x)
+	return c.i, c.v
+}
+
+// Rank returns the number of elements less than x.
+func (e *Set) Rank(x int) int {
+	i, _ := e.NextGEQ(x)
+	return i
+}
+
+// Contains returns true if x is an element of e.
+func (e *Set) Contains(x int) bool {
+	i, v := e.NextGEQ(x)
+	return i < e.n && v == x
+}
+
+// All returns an iterator over the elements of e, in ascending order.
+func (e *Set) All() iter.Seq[int] {
+	return func(yield func(x int) bool) {
+		for c := e.cursor(); c.i < e.n; c.next() {
+			if !yield(c.v) {
+				return
+			}
+		}
+	}
+}
+
+// Ints returns the elements of e as a sorted, duplicate-free slice.
+func (e *Set) Ints() []int {
+	s := make([]int, 0, e.n)
+	for x := range e.All() {
+		s = append(s, x)
+	}
+	return s
+}
+
+// cursor walks the elements of a Set in ascending order.
+type cursor struct {
+	e *Set
+	i int // index of the current element, or Len when exhausted
+	p int // position in high of the current element's one
+	v int // the current element
+}
+
+// cursor returns a cursor positioned at the least element of e.
+func (e *Set) cursor() *cursor {
+	c := &cursor{e: e, p: -1}
+	if e.n > 0 {
+		c.p = e.nextOne(-1)
+		c.v = e.value(0, c.p)
+	}
+	return c
+}
+
+// next moves c to the following element.
+func (c *cursor) next() {
+	if c.i++; c.i < c.e.n {
+		c.p = c.e.nextOne(c.p)
+		c.v = c.e.value(c.i, c.p)
+	}
+}
+
+// skipTo moves c forward to the least element not less than x. Elements
+// whose high bits are less than x's are skipped by jumping directly to the
+// start of x's bucket.
+func (c *cursor) skipTo(x int) {
+	e := c.e
+	if c.i >= e.n || c.v >= x {
+		return
+	}
+	if x > e.last {
+		c.i = e.n
+		return
+	}
+
+	// elements in bucket h follow the h'th zero (counting from one)
+	h := int((uint64(x) - uint64(e.base)) >> e.l)
+	if h > (c.p - c.i) {
+		p := e.selectBit(h-1, true)
+		i := p - (h - 1)
+		if i > c.i {
+			c.i, c.p = i, e.nextOne(p)
+			c.v = e.value(c.i, c.p)
+		}
+	}
+	for c.v < x {
+		c.next()
+	}
+}
+
+// Inter appends to dst the elements in both a and b, returning the
+// extended slice. Each set skips ahead with NextGEQ to the next element of
+// the other, so runs of elements in only one set are never visited.
+func Inter(dst []int, a, b *Set) []int {
+	c, d := a.cursor(), b.cursor()
+	for c.i < a.n && d.i < b.n {
+		switch {
+		case c.v < d.v:
+			c.skipTo(d.v)
+		case d.v < c.v:
+			d.skipTo(c.v)
+		default:
+			dst = append(dst, c.v)
+			c.next()
+			d.next()
+		}
+	}
+	return dst
+}
+
+// InterInts is like Inter, but intersects a with s, which must be sorted
+// and free of duplicates.
+func InterInts(dst []int, a *Set, s []int) []int {
+	c := a.cursor()
+	for j := 0; j < len(s) && c.i < a.n; {
+		switch x := s[j]; {
+		case c.v < x:
+			c.skipTo(x)
+		case x < c.v:
+			j += sort.SearchInts(s[j:], c.v)
+		default:
+			dst = append(dst, x)
+			c.next()
+			j++
+		}
+	}
+	return dst
+}
+
+// Union appends to dst the elements in either a or b, returning the
+// extended slice.
+func Union(dst []int, a, b *Set) []int {
+	c, d := a.cursor(), b.cursor()
+	for c.i < a.n && d.i < b.n {
+		switch {
+		case c.v < d.v:
+			dst = append(dst, c.v)
+			c.next()
+		case d.v < c.v:
+			dst = append(dst, d.v)
+			d.next()
+		default:
+			dst = append(dst, c.v)
+			c.next()
+			d.next()
+		}
+	}
+	for ; c.i < a.n; c.next() {
+		dst = append(dst, c.v)
+	}
+	for ; d.i < b.n; d.next() {
+		dst = append(dst, d.v)
+	}
+	return dst
+}
+
+// UnionInts is like Union, but combines a with s, which must be sorted and
+// free of duplicates.
+func UnionInts(dst []int, a *Set, s []int) []int {
+	c := a.cursor()
+	for _, x := range s {
+		for ; c.i < a.n && c.v < x; c.next() {
+			dst = append(dst, c.v)
+		}
+		if c.i < a.n && c.v == x {
+			c.next()
+		}
+		dst = append(dst, x)
+	}
+	for ; c.i < a.n; c.next() {
+		dst = append(dst, c.v)
+	}
+	return dst
+}
@@ -0,0 +1,224 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eliasfano_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/eliasfano"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+func randInts(rng *rand.Rand, n, max int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = rng.Intn(max) - max/4
+	}
+	return set.Ints(s)
+}
+
+func inputs(rng *rand.Rand) [][]int {
+	sets := [][]int{
+		nil,
+		{0},
+		{-7},
+		{math.MinInt, -1, 0, math.MaxInt},
+		testdata.Seq(0, 2000, 1),
+		testdata.Seq(-100, 100000, 37),
+	}
+	for i := 0; i < 10; i++ {
+		sets = append(sets, randInts(rng, rng.Intn(3000), 1<<(4+rng.Intn(27))))
+	}
+	return sets
+}
+
+func TestQueries(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	for _, s := range inputs(rng) {
+		e := eliasfano.FromInts(s)
+
+		if e.Len() != len(s) {
+			t.Errorf("Len() = %d, want %d", e.Len(), len(s))
+		}
+		if got := e.Ints(); !testdata.IsEqual(got, s) {
+			t.Errorf("Ints() = %v, want %v", got, s)
+		}
+		for i, x := range s {
+			if v := e.Select(i); v != x {
+				t.Errorf("Select(%d) = %d, want %d", i, v, x)
+			}
+		}
+
+		probes := append([]int(nil), s...)
+		for _, x := range s {
+			if x != math.MinInt {
+				probes = append(probes, x-1)
+			}
+			if x != math.MaxInt {
+				probes = append(probes, x+1)
+			}
+		}
+		for _, x := range probes {
+			want := sort.SearchInts(s, x)
+			if r := e.Rank(x); r != want {
+				t.Errorf("Rank(%d) = %d, want %d", x, r, want)
+			}
+			i, v := e.NextGEQ(x)
+			if i != want || (i < len(s) && v != s[i]) {
+				t.Errorf("NextGEQ(%d) = %d, %d; want index %d", x, i, v, want)
+			}
+			in := want < len(s) && s[want] == x
+			if ok := e.Contains(x); ok != in {
+				t.Errorf("Contains(%d) = %t, want %t", x, ok, in)
+			}
+		}
+	}
+}
+
+func TestFromIntsPanic(t *testing.T) {
+	for _, s := range [][]int{{1, 1}, {2, 1}, {1, 3, 2}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("FromInts(%v) did not panic", s)
+				}
+			}()
+			eliasfano.FromInts(s)
+		}()
+	}
+}
+
+func TestInterUnion(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		a := randInts(rng, rng.Intn(3000), 10000)
+		b := randInts(rng, rng.Intn(100), 10000)
+		if i%2 == 0 {
+			a, b = b, a
+		}
+		ea, eb := eliasfano.FromInts(a), eliasfano.FromInts(b)
+
+		want := set.Do(set.Inter, append([]int(nil), a...), b...)
+		if got := eliasfano.Inter(nil, ea, eb); !testdata.IsEqual(got, want) {
+			t.Errorf("Inter(%v, %v) = %v, want %v", a, b, got, want)
+		}
+		if got := eliasfano.InterInts(nil, ea, b); !testdata.IsEqual(got, want) {
+			t.Errorf("InterInts(%v, %v) = %v, want %v", a, b, got, want)
+		}
+
+		want = set.Do(set.Union, append([]int(nil), a...), b...)
+		if got := eliasfano.Union(nil, ea, eb); !testdata.IsEqual(got, want) {
+			t.Errorf("Union(%v, %v) = %v, want %v", a, b, got, want)
+		}
+		if got := eliasfano.UnionInts(nil, ea, b); !testdata.IsEqual(got, want) {
+			t.Errorf("UnionInts(%v, %v) = %v, want %v", a, b, got, want)
+		}
+	}
+}
+
+func ExampleSet_NextGEQ() {
+	e := eliasfano.FromInts([]int{3, 10, 42, 1000})
+
+	i, v := e.NextGEQ(11)
+	fmt.Println(i, v, e.Rank(1000), e.Contains(10))
+
+	// Output: 2 42 3 true
+}
+
+func TestBinary(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, s := range inputs(rng) {
+		b, err := eliasfano.FromInts(s).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// a copy at an odd offset cannot be used in place
+		odd := append(make([]byte, 1, len(b)+1), b...)[1:]
+
+		for name, b := range map[string][]byte{"aligned": b, "unaligned": odd} {
+			e, err := eliasfano.FromBytes(b)
+			if err != nil {
+				t.Fatalf("FromBytes(%s): %v", name, err)
+			}
+			if got := e.Ints(); !testdata.IsEqual(got, s) {
+				t.Errorf("FromBytes(%s) of %v = %v", name, s, got)
+			}
+			if len(s) > 0 && e.Rank(s[len(s)-1]) != len(s)-1 {
+				t.Errorf("FromBytes(%s) of %v: Rank of last = %d", name, s, e.Rank(s[len(s)-1]))
+			}
+		}
+
+		var e eliasfano.Set
+		if err := e.UnmarshalBinary(b); err != nil || !testdata.IsEqual(e.Ints(), s) {
+			t.Errorf("UnmarshalBinary of %v = %v, %v", s, e.Ints(), err)
+		}
+	}
+
+	// viewing a large encoding in place does not copy it
+	b, _ := eliasfano.FromInts(testdata.Seq(0, 1<<20, 3)).MarshalBinary()
+	if n := testing.AllocsPerRun(10, func() { eliasfano.FromBytes(b) }); n > 2 {
+		t.Errorf("FromBytes allocated %v times", n)
+	}
+}
+
+func TestFromBytesInvalid(t *testing.T) {
+	b, _ := eliasfano.FromInts(testdata.Seq(0, 5000, 7)).MarshalBinary()
+	le := binary.LittleEndian
+	corrupt := func(i int, v byte) []byte {
+		c := append([]byte(nil), b...)
+		c[i] ^= v
+		return c
+	}
+	// section returns the offsets of the low bits, high bits, ones and zeros
+	section := func(k int) (i, j int) {
+		i = 9
+		for s := 0; s < k; s++ {
+			i += int(le.Uint64(b[8*(5+s):]))
+		}
+		return 8 * i, 8 * (i + int(le.Uint64(b[8*(5+k):])))
+	}
+	zero := func(i, j int) []byte {
+		c := append([]byte(nil), b...)
+		clear(c[i:j])
+		return c
+	}
+	first := func(i, _ int) int { return i + 8 }
+
+	tests := map[string][]byte{
+		"empty":       nil,
+		"partial":     b[:len(b)-1],
+		"truncated":   b[:len(b)-8],
+		"extended":    append(append([]byte(nil), b...), make([]byte, 8)...),
+		"magic":       corrupt(0, 1),
+		"count":       corrupt(8, 1),
+		"range":       corrupt(24, 0x80),
+		"low bits":    corrupt(32, 1),
+		"section len": corrupt(40, 1),
+		"high":        corrupt(first(section(1)), 1),
+		"zeroed high": zero(section(1)),
+		"ones":        corrupt(first(section(2)), 1),
+		"zeros":       corrupt(first(section(3)), 1),
+	}
+	if math.MaxInt == math.MaxInt32 {
+		// base and last are too large for int, but consistent otherwise
+		c := append([]byte(nil), b...)
+		for _, i := range []int{16, 24} {
+			le.PutUint64(c[i:], le.Uint64(c[i:])+1<<32)
+		}
+		tests["int range"] = c
+	}
+	for name, b := range tests {
+		if _, err := eliasfano.FromBytes(b); err != eliasfano.ErrFormat {
+			t.Errorf("FromBytes(%s) error = %v, want %v", name, err, eliasfano.ErrFormat)
+		}
+	}
+}
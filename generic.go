@@ -0,0 +1,83 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ordered adapts a slice of ordered elements to sort.Interface.
+type ordered[T cmp.Ordered] []T
+
+func (s ordered[T]) Len() int           { return len(s) }
+func (s ordered[T]) Less(i, j int) bool { return cmp.Less(s[i], s[j]) }
+func (s ordered[T]) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// funcs adapts a slice and a comparison function to sort.Interface.
+type funcs[T any] struct {
+	s   []T
+	cmp func(a, b T) int
+}
+
+func (f funcs[T]) Len() int           { return len(f.s) }
+func (f funcs[T]) Less(i, j int) bool { return f.cmp(f.s[i], f.s[j]) < 0 }
+func (f funcs[T]) Swap(i, j int)      { f.s[i], f.s[j] = f.s[j], f.s[i] }
+
+// Normalize sorts and deduplicates a slice in place, returning the
+// resulting set.
+func Normalize[T cmp.Ordered](data []T) []T {
+	slices.Sort(data)
+	return UniqSlice(data)
+}
+
+// NormalizeFunc is like Normalize, but orders elements using cmp, which
+// must return a negative number when a < b, a positive number when a > b,
+// and zero when a and b are equal.
+func NormalizeFunc[T any](data []T, cmp func(a, b T) int) []T {
+	slices.SortFunc(data, cmp)
+	return UniqSliceFunc(data, cmp)
+}
+
+// UniqSlice swaps away duplicate elements in the pre-sorted data,
+// returning the resulting set.
+func UniqSlice[T cmp.Ordered](data []T) []T {
+	n := Uniq(ordered[T](data))
+	return data[:n]
+}
+
+// UniqSliceFunc is like UniqSlice, but compares elements using cmp.
+func UniqSliceFunc[T any](data []T, cmp func(a, b T) int) []T {
+	n := Uniq(funcs[T]{data, cmp})
+	return data[:n]
+}
+
+// Do applies op to the sets, s and t, returning the result.
+// s and t must already be individually sorted and free of duplicates.
+// As with IntsDo, s may be overwritten.
+func Do[T cmp.Ordered](op Op, s []T, t ...T) []T {
+	data := append(s, t...)
+	n := op(ordered[T](data), len(s))
+	return data[:n]
+}
+
+// DoFunc is like Do, but compares elements using cmp. s and t must
+// already be individually sorted by cmp and free of duplicates.
+func DoFunc[T any](op Op, cmp func(a, b T) int, s []T, t ...T) []T {
+	data := append(s, t...)
+	n := op(funcs[T]{data, cmp}, len(s))
+	return data[:n]
+}
+
+// Chk compares s and t according to chk. Neither s nor t is modified.
+func Chk[T cmp.Ordered](chk Cmp, s []T, t ...T) bool {
+	return chk(pair[T]{s, t, cmp.Less[T]}, len(s))
+}
+
+// ChkFunc is like Chk, but compares elements using fn.
+func ChkFunc[T any](cmp Cmp, fn func(a, b T) int, s []T, t ...T) bool {
+	less := func(a, b T) bool { return fn(a, b) < 0 }
+	return cmp(pair[T]{s, t, less}, len(s))
+}
@@ -0,0 +1,107 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+var (
+	ops = map[string]set.Op{
+		"Union":   set.Union,
+		"Inter":   set.Inter,
+		"Diff":    set.Diff,
+		"SymDiff": set.SymDiff,
+	}
+	cmps = map[string]set.Cmp{
+		"IsSub":   set.IsSub,
+		"IsSuper": set.IsSuper,
+		"IsInter": set.IsInter,
+		"IsEqual": set.IsEqual,
+	}
+)
+
+func revcmp(a, b int) int { return cmp.Compare(b, a) }
+
+func reversed(s []int) []int {
+	s = append([]int(nil), s...)
+	slices.Reverse(s)
+	return s
+}
+
+func TestNormalize(t *testing.T) {
+	for _, tt := range testdata.UniqTests {
+		s := reversed(tt.In)
+		s = set.Normalize(s)
+
+		if !testdata.IsEqual(s, tt.Out) {
+			t.Errorf("Normalize(%v) = %v, want %v", tt.In, s, tt.Out)
+		}
+
+		s = reversed(tt.In)
+		s = set.NormalizeFunc(s, revcmp)
+		want := reversed(tt.Out)
+
+		if !testdata.IsEqual(s, want) {
+			t.Errorf("NormalizeFunc(%v) = %v, want %v", tt.In, s, want)
+		}
+	}
+}
+
+func TestDo(t *testing.T) {
+	for name, op := range ops {
+		for _, tt := range testdata.BinTests {
+			a := append([]int(nil), tt.A...)
+			c := set.Do(op, a, tt.B...)
+			want := tt.SelSlice(name)
+
+			if !testdata.IsEqual(c, want) {
+				t.Errorf(format, name, tt.A, tt.B, c, want)
+			}
+
+			c = set.DoFunc(op, revcmp, reversed(tt.A), reversed(tt.B)...)
+			want = reversed(want)
+
+			if !testdata.IsEqual(c, want) {
+				t.Errorf(format, name+"Func", tt.A, tt.B, c, want)
+			}
+		}
+	}
+}
+
+func TestChk(t *testing.T) {
+	for name, chk := range cmps {
+		for _, tt := range testdata.BinTests {
+			ok := set.Chk(chk, tt.A, tt.B...)
+			want := tt.SelBool(name)
+
+			if ok != want {
+				t.Errorf(format, name, tt.A, tt.B, ok, want)
+			}
+
+			ok = set.ChkFunc(chk, revcmp, reversed(tt.A), reversed(tt.B)...)
+
+			if ok != want {
+				t.Errorf(format, name+"Func", tt.A, tt.B, ok, want)
+			}
+		}
+	}
+}
+
+func ExampleDo() {
+	type id uint32
+
+	s := set.Normalize([]id{7, 3, 5, 3})
+	s = set.Do(set.Union, s, 1, 4)
+	fmt.Println(s)
+
+	// Output: [1 3 4 5 7]
+}
@@ -32,6 +32,11 @@ func Strings(data []string) []string {
 
 // IntsDo applies op to the int sets, s and t, returning the result.
 // s and t must already be individually sorted and free of duplicates.
+//
+// The result is computed in place within s's backing array, so both the
+// elements of s and any spare capacity beyond len(s) may be overwritten.
+// Passing s[:len(s):len(s)] forces a copy and leaves s untouched;
+// alternatively, UnionInto and related functions never modify their inputs.
 func IntsDo(op Op, s []int, t ...int) []int {
 	data := sort.IntSlice(append(s, t...))
 	n := op(data, len(s))
@@ -40,6 +45,7 @@ func IntsDo(op Op, s []int, t ...int) []int {
 
 // Float64sDo applies op to the float64 sets, s and t, returning the result.
 // s and t must already be individually sorted and free of duplicates.
+// As with IntsDo, s may be overwritten.
 func Float64sDo(op Op, s []float64, t ...float64) []float64 {
 	data := sort.Float64Slice(append(s, t...))
 	n := op(data, len(s))
@@ -48,26 +54,72 @@ func Float64sDo(op Op, s []float64, t ...float64) []float64 {
 
 // StringsDo applies op to the string sets, s and t, returning the result.
 // s and t must already be individually sorted and free of duplicates.
+// As with IntsDo, s may be overwritten.
 func StringsDo(op Op, s []string, t ...string) []string {
 	data := sort.StringSlice(append(s, t...))
 	n := op(data, len(s))
 	return data[:n]
 }
 
-// IntsChk compares s and t according to cmp.
+// IntsChk compares s and t according to cmp. Neither s nor t is modified.
 func IntsChk(cmp Cmp, s []int, t ...int) bool {
-	data := sort.IntSlice(append(s, t...))
-	return cmp(data, len(s))
+	return Chk(cmp, s, t...)
 }
 
-// Float64sChk compares s and t according to cmp.
+// Float64sChk compares s and t according to cmp. Neither s nor t is modified.
 func Float64sChk(cmp Cmp, s []float64, t ...float64) bool {
-	data := sort.Float64Slice(append(s, t...))
-	return cmp(data, len(s))
+	return Chk(cmp, s, t...)
 }
 
-// StringsChk compares s and t according to cmp.
+// StringsChk compares s and t according to cmp. Neither s nor t is modified.
 func StringsChk(cmp Cmp, s []string, t ...string) bool {
-	data := sort.StringSlice(append(s, t...))
-	return cmp(data, len(s))
+	return Chk(cmp, s, t...)
+}
+
+// IntsInsert adds x to the int set s; see Insert.
+func IntsInsert(s []int, x int) ([]int, bool) { return Insert(s, x) }
+
+// IntsInsertMany adds elems to the int set s; see InsertMany.
+func IntsInsertMany(s []int, elems ...int) ([]int, bool) {
+	return InsertMany(s, elems...)
+}
+
+// IntsRemove deletes x from the int set s; see Remove.
+func IntsRemove(s []int, x int) ([]int, bool) { return Remove(s, x) }
+
+// IntsRemoveMany deletes elems from the int set s; see RemoveMany.
+func IntsRemoveMany(s []int, elems ...int) ([]int, bool) {
+	return RemoveMany(s, elems...)
+}
+
+// Float64sInsert adds x to the float64 set s; see Insert.
+func Float64sInsert(s []float64, x float64) ([]float64, bool) { return Insert(s, x) }
+
+// Float64sInsertMany adds elems to the float64 set s; see InsertMany.
+func Float64sInsertMany(s []float64, elems ...float64) ([]float64, bool) {
+	return InsertMany(s, elems...)
+}
+
+// Float64sRemove deletes x from the float64 set s; see Remove.
+func Float64sRemove(s []float64, x float64) ([]float64, bool) { return Remove(s, x) }
+
+// Float64sRemoveMany deletes elems from the float64 set s; see RemoveMany.
+func Float64sRemoveMany(s []float64, elems ...float64) ([]float64, bool) {
+	return RemoveMany(s, elems...)
+}
+
+// StringsInsert adds x to the string set s; see Insert.
+func StringsInsert(s []string, x string) ([]string, bool) { return Insert(s, x) }
+
+// StringsInsertMany adds elems to the string set s; see InsertMany.
+func StringsInsertMany(s []string, elems ...string) ([]string, bool) {
+	return InsertMany(s, elems...)
+}
+
+// StringsRemove deletes x from the string set s; see Remove.
+func StringsRemove(s []string, x string) ([]string, bool) { return Remove(s, x) }
+
+// StringsRemoveMany deletes elems from the string set s; see RemoveMany.
+func StringsRemoveMany(s []string, elems ...string) ([]string, bool) {
+	return RemoveMany(s, elems...)
 }
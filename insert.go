@@ -0,0 +1,58 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Insert adds x to the set s, returning the resulting set, and true if x
+// was not already present. The insertion position is found with a binary
+// search, and later elements are shifted up; as with append, s's backing
+// array is reused if it has sufficient capacity.
+func Insert[T cmp.Ordered](s []T, x T) ([]T, bool) {
+	i, ok := slices.BinarySearch(s, x)
+	if ok {
+		return s, false
+	}
+	return slices.Insert(s, i, x), true
+}
+
+// InsertMany adds elems, which may be in any order and may contain
+// duplicates, to the set s. It returns the resulting set, and true if any
+// element was not already present. elems is not modified. Multiple
+// elements are inserted with a single merge rather than one at a time.
+func InsertMany[T cmp.Ordered](s []T, elems ...T) ([]T, bool) {
+	if len(elems) == 1 {
+		return Insert(s, elems[0])
+	}
+	n := len(s)
+	s = Do(Union, s, Normalize(slices.Clone(elems))...)
+	return s, len(s) != n
+}
+
+// Remove deletes x from the set s, returning the resulting set, and true
+// if x was present. Later elements are shifted down in place.
+func Remove[T cmp.Ordered](s []T, x T) ([]T, bool) {
+	i, ok := slices.BinarySearch(s, x)
+	if !ok {
+		return s, false
+	}
+	return slices.Delete(s, i, i+1), true
+}
+
+// RemoveMany deletes elems, which may be in any order and may contain
+// duplicates, from the set s. It returns the resulting set, and true if
+// any element was present. elems is not modified. Multiple elements are
+// removed in a single pass over s.
+func RemoveMany[T cmp.Ordered](s []T, elems ...T) ([]T, bool) {
+	if len(elems) == 1 {
+		return Remove(s, elems[0])
+	}
+	n := len(s)
+	s = DiffInto(s[:0], s, Normalize(slices.Clone(elems)))
+	return s, len(s) != n
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/mapset"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+func TestInsertRemove(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	ref := mapset.Set{}
+	var s []int
+
+	for i := 0; i < 2000; i++ {
+		elems := make([]int, rng.Intn(4))
+		for j := range elems {
+			elems[j] = rng.Intn(64)
+		}
+
+		var changed bool
+		before := len(ref)
+
+		if rng.Intn(2) == 0 {
+			s, changed = set.InsertMany(s, elems...)
+			ref.Union(mapset.New(elems))
+		} else {
+			s, changed = set.RemoveMany(s, elems...)
+			ref.Diff(mapset.New(elems))
+		}
+
+		if want := ref.Elems(); !testdata.IsEqual(s, want) {
+			t.Fatalf("after %v: got %v, want %v", elems, s, want)
+		}
+		if changed != (len(ref) != before) {
+			t.Fatalf("after %v: changed = %t", elems, changed)
+		}
+	}
+}
+
+func ExampleIntsInsert() {
+	s := set.Ints([]int{3, 1, 4})
+
+	s, added := set.IntsInsert(s, 2)
+	fmt.Println(s, added)
+
+	s, added = set.IntsInsert(s, 4)
+	fmt.Println(s, added)
+
+	s, removed := set.IntsRemoveMany(s, 1, 4, 9)
+	fmt.Println(s, removed)
+
+	// Output:
+	// [1 2 3 4] true
+	// [1 2 3 4] false
+	// [2 3] true
+}
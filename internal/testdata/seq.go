@@ -4,7 +4,10 @@
 
 package testdata
 
-import "math/rand"
+import (
+	"math/rand"
+	"slices"
+)
 
 const (
 	Small = 32
@@ -62,6 +65,12 @@ func Overlap(n int, size int) [][]int {
 	return Concat(n, size, -size/2)
 }
 
+func Skew(small, large int) [][]int {
+	// inter, diff: a small set spread evenly across a large one; favors
+	// galloping over linear scans
+	return [][]int{Seq(0, large, large/small), Seq(0, large, 1)}
+}
+
 func Rand(n int, size int) [][]int {
 	rand.Seed(0)
 	sets := make([][]int, n)
@@ -72,3 +81,16 @@ func Rand(n int, size int) [][]int {
 	}
 	return sets
 }
+
+// RandSet returns a sorted, duplicate-free set of fewer than 64 elements
+// drawn from a random range of at most 128, so that sets drawn in turn
+// vary in size, density and overlap.
+func RandSet(rng *rand.Rand) []int {
+	n, m := rng.Intn(64), 1+rng.Intn(128)
+	s := make([]int, n)
+	for i := range s {
+		s[i] = rng.Intn(m)
+	}
+	slices.Sort(s)
+	return slices.Compact(s)
+}
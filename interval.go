@@ -0,0 +1,213 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Interval represents the half-open interval [Lo, Hi), which is empty if
+// Hi is not greater than Lo.
+type Interval[T cmp.Ordered] struct{ Lo, Hi T }
+
+// IntervalSet is a set represented by a sorted slice of non-empty,
+// non-overlapping, non-adjacent intervals, which allows large runs of
+// elements to be stored, and operated upon, as a single interval. The zero
+// value is an empty set. As with Set, methods that return an IntervalSet
+// allocate a new one, leaving their receiver and arguments untouched.
+type IntervalSet[T cmp.Ordered] []Interval[T]
+
+// NewIntervalSet returns the set of elements covered by any of spans,
+// which may be in any order, and may be empty, overlap, or abut one
+// another; overlapping and adjacent intervals are coalesced. spans is not
+// modified.
+func NewIntervalSet[T cmp.Ordered](spans ...Interval[T]) IntervalSet[T] {
+	spans = slices.Clone(spans)
+	slices.SortFunc(spans, func(a, b Interval[T]) int { return cmp.Compare(a.Lo, b.Lo) })
+
+	var s IntervalSet[T]
+	for _, v := range spans {
+		if !cmp.Less(v.Lo, v.Hi) {
+			continue
+		}
+		s = s.appendSpan(v.Lo, v.Hi)
+	}
+	return s
+}
+
+// ErrMaxInt is returned by IntervalsFromInts for a set containing
+// math.MaxInt, which no half-open interval can cover.
+var ErrMaxInt = errors.New("set: math.MaxInt cannot be covered by a half-open interval")
+
+// IntervalsFromInts converts the int set s, which must be sorted and free
+// of duplicates, into an IntervalSet, with each run of consecutive
+// integers becoming one interval. It returns ErrMaxInt if s contains
+// math.MaxInt.
+func IntervalsFromInts(s []int) (IntervalSet[int], error) {
+	if len(s) > 0 && s[len(s)-1] == math.MaxInt {
+		return nil, ErrMaxInt
+	}
+	var t IntervalSet[int]
+	for i := 0; i < len(s); {
+		j := i + 1
+		for j < len(s) && s[j] == s[j-1]+1 {
+			j++
+		}
+		t = append(t, Interval[int]{s[i], s[j-1] + 1})
+		i = j
+	}
+	return t, nil
+}
+
+// IntsFromIntervals converts s into a sorted, duplicate-free int set
+// containing every integer covered by s.
+func IntsFromIntervals(s IntervalSet[int]) []int {
+	var t []int
+	for _, v := range s {
+		for x := v.Lo; x < v.Hi; x++ {
+			t = append(t, x)
+		}
+	}
+	return t
+}
+
+// Contains returns true if x is covered by any interval in s.
+func (s IntervalSet[T]) Contains(x T) bool {
+	i := sort.Search(len(s), func(i int) bool { return cmp.Less(x, s[i].Hi) })
+	return i < len(s) && !cmp.Less(x, s[i].Lo)
+}
+
+// Complement returns the set of elements in [lo, hi) not covered by s.
+func (s IntervalSet[T]) Complement(lo, hi T) IntervalSet[T] {
+	return NewIntervalSet(Interval[T]{lo, hi}).Diff(s)
+}
+
+// Union returns the set of elements in either s or t.
+func (s IntervalSet[T]) Union(t IntervalSet[T]) IntervalSet[T] {
+	return s.combine(t, func(a, b bool) bool { return a || b })
+}
+
+// Inter returns the set of elements in both s and t.
+func (s IntervalSet[T]) Inter(t IntervalSet[T]) IntervalSet[T] {
+	return s.combine(t, func(a, b bool) bool { return a && b })
+}
+
+// Diff returns the set of elements in s but not in t.
+func (s IntervalSet[T]) Diff(t IntervalSet[T]) IntervalSet[T] {
+	return s.combine(t, func(a, b bool) bool { return a && !b })
+}
+
+// SymDiff returns the set of elements in exactly one of s and t.
+func (s IntervalSet[T]) SymDiff(t IntervalSet[T]) IntervalSet[T] {
+	return s.combine(t, func(a, b bool) bool { return a != b })
+}
+
+// IsSub returns true only if all elements of s are also in t.
+func (s IntervalSet[T]) IsSub(t IntervalSet[T]) bool {
+	return !s.any(t, func(a, b bool) bool { return a && !b })
+}
+
+// IsSuper returns true only if all elements of t are also in s.
+func (s IntervalSet[T]) IsSuper(t IntervalSet[T]) bool {
+	return !s.any(t, func(a, b bool) bool { return !a && b })
+}
+
+// IsInter returns true if any element of s is also in t.
+func (s IntervalSet[T]) IsInter(t IntervalSet[T]) bool {
+	return s.any(t, func(a, b bool) bool { return a && b })
+}
+
+// IsEqual returns true if s and t contain the same elements.
+func (s IntervalSet[T]) IsEqual(t IntervalSet[T]) bool {
+	return !s.any(t, func(a, b bool) bool { return a != b })
+}
+
+// combine returns the set of segments whose membership in s and t is
+// accepted by keep.
+func (s IntervalSet[T]) combine(t IntervalSet[T], keep func(a, b bool) bool) IntervalSet[T] {
+	var u IntervalSet[T]
+	s.sweep(t, func(lo, hi T, a, b bool) bool {
+		if keep(a, b) {
+			u = u.appendSpan(lo, hi)
+		}
+		return true
+	})
+	return u
+}
+
+// any returns true if the membership of any segment in s and t is
+// accepted by pred.
+func (s IntervalSet[T]) any(t IntervalSet[T], pred func(a, b bool) bool) bool {
+	found := false
+	s.sweep(t, func(lo, hi T, a, b bool) bool {
+		found = pred(a, b)
+		return !found
+	})
+	return found
+}
+
+// sweep calls fn, in order, for each non-empty segment [lo, hi) between
+// consecutive interval endpoints of s and t that is covered by either,
+// along with whether it is covered by s and by t, stopping early if fn
+// returns false.
+func (s IntervalSet[T]) sweep(t IntervalSet[T], fn func(lo, hi T, a, b bool) bool) {
+	i, j := 0, 0
+	a, b := false, false // whether s and t cover the current segment
+	var lo T
+	for i < len(s) || j < len(t) {
+		var x T
+		switch {
+		case i == len(s):
+			x = t.bound(j, b)
+		case j == len(t):
+			x = s.bound(i, a)
+		default:
+			x = min(s.bound(i, a), t.bound(j, b))
+		}
+
+		if (a || b) && cmp.Less(lo, x) && !fn(lo, x, a, b) {
+			return
+		}
+
+		if i < len(s) && cmp.Compare(s.bound(i, a), x) == 0 {
+			if a {
+				i++
+			}
+			a = !a
+		}
+		if j < len(t) && cmp.Compare(t.bound(j, b), x) == 0 {
+			if b {
+				j++
+			}
+			b = !b
+		}
+		lo = x
+	}
+}
+
+// bound returns the next endpoint of interval i: its end if inside is
+// true, or else its start.
+func (s IntervalSet[T]) bound(i int, inside bool) T {
+	if inside {
+		return s[i].Hi
+	}
+	return s[i].Lo
+}
+
+// appendSpan appends [lo, hi) to s, coalescing it with the last interval
+// in s if they overlap or abut. lo must not be less than the start of the
+// last interval.
+func (s IntervalSet[T]) appendSpan(lo, hi T) IntervalSet[T] {
+	n := len(s) - 1
+	if n < 0 || cmp.Less(s[n].Hi, lo) {
+		return append(s, Interval[T]{lo, hi})
+	}
+	s[n].Hi = max(s[n].Hi, hi)
+	return s
+}
@@ -0,0 +1,115 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+func TestIntervalSet(t *testing.T) {
+	type I = set.IntervalSet[int]
+
+	muts := map[string]func(a, b I) I{
+		"Union":   I.Union,
+		"Inter":   I.Inter,
+		"Diff":    I.Diff,
+		"SymDiff": I.SymDiff,
+	}
+	bools := map[string]func(a, b I) bool{
+		"IsSub":   I.IsSub,
+		"IsSuper": I.IsSuper,
+		"IsInter": I.IsInter,
+		"IsEqual": I.IsEqual,
+	}
+
+	rng := rand.New(rand.NewSource(0))
+	randSet := func() []int {
+		var s []int
+		for x := 0; x < 64; x++ {
+			if rng.Intn(3) == 0 {
+				s = append(s, x)
+			}
+		}
+		return s
+	}
+
+	for i := 0; i < 200; i++ {
+		a, b := randSet(), randSet()
+		ia, _ := set.IntervalsFromInts(a)
+		ib, _ := set.IntervalsFromInts(b)
+
+		if c := set.IntsFromIntervals(ia); !testdata.IsEqual(c, a) {
+			t.Fatalf("round trip of %v = %v", a, c)
+		}
+
+		for name, op := range muts {
+			want := set.Do(ops[name], append([]int(nil), a...), b...)
+
+			c := op(ia, ib)
+			if got := set.IntsFromIntervals(c); !testdata.IsEqual(got, want) {
+				t.Fatalf(format, name, a, b, got, want)
+			}
+			if w, _ := set.IntervalsFromInts(want); fmt.Sprint(c) != fmt.Sprint(w) {
+				t.Fatalf("%s(%v, %v) = %v, which is not coalesced", name, ia, ib, c)
+			}
+		}
+
+		for name, op := range bools {
+			want := set.Chk(cmps[name], a, b...)
+
+			if got := op(ia, ib); got != want {
+				t.Fatalf(format, name, a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestIntervalsFromIntsRange(t *testing.T) {
+	// half-open intervals cannot cover math.MaxInt, but reach up to it
+	for _, s := range [][]int{
+		{math.MinInt},
+		{math.MinInt, math.MinInt + 1, 0},
+		{math.MaxInt - 2, math.MaxInt - 1},
+	} {
+		i, err := set.IntervalsFromInts(s)
+		if c := set.IntsFromIntervals(i); err != nil || !testdata.IsEqual(c, s) {
+			t.Errorf("round trip of %v = %v, %v", s, c, err)
+		}
+	}
+
+	for _, s := range [][]int{{math.MaxInt}, {0, math.MaxInt - 1, math.MaxInt}} {
+		if i, err := set.IntervalsFromInts(s); err != set.ErrMaxInt {
+			t.Errorf("IntervalsFromInts(%v) = %v, %v, want %v", s, i, err, set.ErrMaxInt)
+		}
+	}
+}
+
+func TestIntervalSetContains(t *testing.T) {
+	s := set.NewIntervalSet(set.Interval[float64]{0.5, 1}, set.Interval[float64]{2, 3})
+
+	for x, want := range map[float64]bool{0: false, 0.5: true, 0.99: true, 1: false, 2.5: true, 3: false} {
+		if got := s.Contains(x); got != want {
+			t.Errorf("%v.Contains(%v) = %t, want %t", s, x, got, want)
+		}
+	}
+}
+
+func ExampleNewIntervalSet() {
+	type I = set.Interval[int]
+
+	s := set.NewIntervalSet(I{10, 20}, I{0, 5}, I{5, 8}, I{15, 25}, I{30, 30})
+	fmt.Println(s)
+	fmt.Println(s.Complement(0, 40))
+
+	// Output:
+	// [{0 8} {10 25}]
+	// [{8 10} {25 40}]
+}
@@ -0,0 +1,95 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import "cmp"
+
+// selections for mergeInto, describing which elements to keep.
+const (
+	onlyA = 1 << iota
+	onlyB
+	both
+)
+
+// UnionInto appends the union of the sets a and b to dst, returning the
+// updated slice. a and b must already be individually sorted and free of
+// duplicates, and are not modified. As with append, dst's spare capacity
+// is reused when sufficient; dst must not overlap a or b.
+func UnionInto[T cmp.Ordered](dst, a, b []T) []T {
+	return mergeInto(dst, a, b, cmp.Compare[T], onlyA|onlyB|both)
+}
+
+// InterInto appends the intersection of the sets a and b to dst, returning
+// the updated slice. Aside from the operation, it behaves like UnionInto,
+// except that dst may also be a[:0], which filters a in place.
+func InterInto[T cmp.Ordered](dst, a, b []T) []T {
+	return mergeInto(dst, a, b, cmp.Compare[T], both)
+}
+
+// DiffInto appends the difference of the sets a and b to dst, returning
+// the updated slice. Aside from the operation, it behaves like UnionInto,
+// except that dst may also be a[:0], which filters a in place.
+func DiffInto[T cmp.Ordered](dst, a, b []T) []T {
+	return mergeInto(dst, a, b, cmp.Compare[T], onlyA)
+}
+
+// SymDiffInto appends the symmetric difference of the sets a and b to dst,
+// returning the updated slice. Aside from the operation, it behaves like
+// UnionInto.
+func SymDiffInto[T cmp.Ordered](dst, a, b []T) []T {
+	return mergeInto(dst, a, b, cmp.Compare[T], onlyA|onlyB)
+}
+
+// UnionIntoFunc is like UnionInto, but compares elements using cmp.
+func UnionIntoFunc[T any](dst, a, b []T, cmp func(x, y T) int) []T {
+	return mergeInto(dst, a, b, cmp, onlyA|onlyB|both)
+}
+
+// InterIntoFunc is like InterInto, but compares elements using cmp.
+func InterIntoFunc[T any](dst, a, b []T, cmp func(x, y T) int) []T {
+	return mergeInto(dst, a, b, cmp, both)
+}
+
+// DiffIntoFunc is like DiffInto, but compares elements using cmp.
+func DiffIntoFunc[T any](dst, a, b []T, cmp func(x, y T) int) []T {
+	return mergeInto(dst, a, b, cmp, onlyA)
+}
+
+// SymDiffIntoFunc is like SymDiffInto, but compares elements using cmp.
+func SymDiffIntoFunc[T any](dst, a, b []T, cmp func(x, y T) int) []T {
+	return mergeInto(dst, a, b, cmp, onlyA|onlyB)
+}
+
+// mergeInto walks a and b in step, appending to dst those elements whose
+// membership matches the keep selection.
+func mergeInto[T any](dst, a, b []T, cmp func(x, y T) int, keep int) []T {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := cmp(a[i], b[j]); {
+		case c < 0:
+			if keep&onlyA != 0 {
+				dst = append(dst, a[i])
+			}
+			i++
+		case c > 0:
+			if keep&onlyB != 0 {
+				dst = append(dst, b[j])
+			}
+			j++
+		default:
+			if keep&both != 0 {
+				dst = append(dst, a[i])
+			}
+			i, j = i+1, j+1
+		}
+	}
+	if keep&onlyA != 0 {
+		dst = append(dst, a[i:]...)
+	}
+	if keep&onlyB != 0 {
+		dst = append(dst, b[j:]...)
+	}
+	return dst
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+func TestInto(t *testing.T) {
+	intos := map[string]func(dst, a, b []int) []int{
+		"Union":   set.UnionInto[int],
+		"Inter":   set.InterInto[int],
+		"Diff":    set.DiffInto[int],
+		"SymDiff": set.SymDiffInto[int],
+	}
+	funcs := map[string]func(dst, a, b []int, cmp func(x, y int) int) []int{
+		"Union":   set.UnionIntoFunc[int],
+		"Inter":   set.InterIntoFunc[int],
+		"Diff":    set.DiffIntoFunc[int],
+		"SymDiff": set.SymDiffIntoFunc[int],
+	}
+
+	for name, into := range intos {
+		for _, tt := range testdata.BinTests {
+			// spare capacity in a must not be touched
+			a := append(make([]int, 0, len(tt.A)+len(tt.B)), tt.A...)
+			a = append(a, -1)[:len(tt.A)]
+
+			dst := []int{-2}
+			c := into(dst, a, tt.B)
+			want := append([]int{-2}, tt.SelSlice(name)...)
+
+			if !testdata.IsEqual(c, want) {
+				t.Errorf(format, name+"Into", tt.A, tt.B, c, want)
+			}
+			if !testdata.IsEqual(a, tt.A) || a[:len(a)+1][len(a)] != -1 {
+				t.Errorf("%sInto(%v, %v) modified its input", name, tt.A, tt.B)
+			}
+
+			c = funcs[name](nil, reversed(tt.A), reversed(tt.B), revcmp)
+			want = reversed(tt.SelSlice(name))
+
+			if !testdata.IsEqual(c, want) {
+				t.Errorf(format, name+"IntoFunc", tt.A, tt.B, c, want)
+			}
+		}
+	}
+}
+
+func TestIntoInPlace(t *testing.T) {
+	for _, tt := range testdata.BinTests {
+		a := append([]int(nil), tt.A...)
+		c := set.InterInto(a[:0], a, tt.B)
+
+		if !testdata.IsEqual(c, tt.Inter) {
+			t.Errorf(format, "InterInto", tt.A, tt.B, c, tt.Inter)
+		}
+
+		a = append([]int(nil), tt.A...)
+		c = set.DiffInto(a[:0], a, tt.B)
+
+		if !testdata.IsEqual(c, tt.Diff) {
+			t.Errorf(format, "DiffInto", tt.A, tt.B, c, tt.Diff)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package join implements sorted merge joins over the same data layout
+// used by package set: data represents a left relation in the range
+// [0:pivot] and a right relation in the range [pivot:Len], each sorted by
+// key. Unlike sets, either relation may contain duplicate keys.
+//
+// Joins do not modify data. Each join returns an iterator over pairs of
+// indices (i, j), with i in [0:pivot] and j in [pivot:Len], in key order;
+// within a run of equal keys, every left index is paired with every right
+// index, in index order. Where an outer join finds no match for a row, the
+// missing side of the pair is -1.
+//
+// Elements are considered equal if `!Less(i,j) && !Less(j,i)`.
+package join
+
+import (
+	"iter"
+	"sort"
+
+	"github.com/xtgo/set"
+)
+
+// selections of unmatched rows to be emitted.
+const (
+	left = 1 << iota
+	right
+	matched
+)
+
+// Inner returns an iterator over the pairs of rows with equal keys.
+func Inner(data sort.Interface, pivot int) iter.Seq2[int, int] {
+	return join(data, pivot, matched)
+}
+
+// Left returns an iterator over the pairs of rows with equal keys, along
+// with (i, -1) for each left row i with no matching right row.
+func Left(data sort.Interface, pivot int) iter.Seq2[int, int] {
+	return join(data, pivot, matched|left)
+}
+
+// Right returns an iterator over the pairs of rows with equal keys, along
+// with (-1, j) for each right row j with no matching left row.
+func Right(data sort.Interface, pivot int) iter.Seq2[int, int] {
+	return join(data, pivot, matched|right)
+}
+
+// Full returns an iterator over the pairs of rows with equal keys, along
+// with the unmatched rows of both sides, as Left and Right do.
+func Full(data sort.Interface, pivot int) iter.Seq2[int, int] {
+	return join(data, pivot, matched|left|right)
+}
+
+// Anti returns an iterator over (i, -1) for each left row i with no
+// matching right row; this is the anti-join corresponding to set.Diff.
+func Anti(data sort.Interface, pivot int) iter.Seq2[int, int] {
+	return join(data, pivot, left)
+}
+
+// join merges the runs of equal keys in the two relations, emitting the
+// selected kinds of rows.
+func join(data sort.Interface, pivot int, sel int) iter.Seq2[int, int] {
+	return func(yield func(i, j int) bool) {
+		nextL, stopL := iter.Pull2(set.Runs(window{data, 0, pivot}))
+		defer stopL()
+		nextR, stopR := iter.Pull2(set.Runs(window{data, pivot, data.Len()}))
+		defer stopR()
+
+		// the current runs are [i:i+m] and [pivot+j:pivot+j+n]
+		i, m, okL := nextL()
+		j, n, okR := nextR()
+		for okL && (okR || sel&left != 0) || okR && sel&right != 0 {
+			a, b := i, pivot+j
+			switch {
+			case !okR || okL && data.Less(a, b):
+				for x := a; sel&left != 0 && x < a+m; x++ {
+					if !yield(x, -1) {
+						return
+					}
+				}
+				i, m, okL = nextL()
+			case !okL || data.Less(b, a):
+				for y := b; sel&right != 0 && y < b+n; y++ {
+					if !yield(-1, y) {
+						return
+					}
+				}
+				j, n, okR = nextR()
+			default:
+				if sel&matched != 0 && !product(yield, a, a+m, b, b+n) {
+					return
+				}
+				i, m, okL = nextL()
+				j, n, okR = nextR()
+			}
+		}
+	}
+}
+
+// product yields every pair of indices from [i:m] and [j:n], returning
+// false if yield asked to stop.
+func product(yield func(i, j int) bool, i, m, j, n int) bool {
+	for x := i; x < m; x++ {
+		for y := j; y < n; y++ {
+			if !yield(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// window presents the elements of data in [i:j] as a sort.Interface of
+// their own.
+type window struct {
+	data sort.Interface
+	i, j int
+}
+
+func (w window) Len() int           { return w.j - w.i }
+func (w window) Less(i, j int) bool { return w.data.Less(w.i+i, w.i+j) }
+func (w window) Swap(i, j int)      { w.data.Swap(w.i+i, w.i+j) }
@@ -0,0 +1,163 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package join_test
+
+import (
+	"fmt"
+	"iter"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/xtgo/set/join"
+)
+
+func pairs(seq iter.Seq2[int, int]) string {
+	var b strings.Builder
+	for i, j := range seq {
+		fmt.Fprintf(&b, "(%d,%d)", i, j)
+	}
+	return b.String()
+}
+
+func TestJoin(t *testing.T) {
+	// left keys occupy [0:5], right keys occupy [5:10]
+	data := sort.IntSlice{1, 2, 2, 4, 6, 2, 2, 3, 4, 7}
+	pivot := 5
+
+	tests := []struct {
+		name string
+		fn   func(sort.Interface, int) iter.Seq2[int, int]
+		want string
+	}{
+		{"Inner", join.Inner, "(1,5)(1,6)(2,5)(2,6)(3,8)"},
+		{"Left", join.Left, "(0,-1)(1,5)(1,6)(2,5)(2,6)(3,8)(4,-1)"},
+		{"Right", join.Right, "(1,5)(1,6)(2,5)(2,6)(-1,7)(3,8)(-1,9)"},
+		{"Full", join.Full, "(0,-1)(1,5)(1,6)(2,5)(2,6)(-1,7)(3,8)(4,-1)(-1,9)"},
+		{"Anti", join.Anti, "(0,-1)(4,-1)"},
+	}
+
+	for _, tt := range tests {
+		if got := pairs(tt.fn(data, pivot)); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+
+		// stopping early must be honored
+		n := 0
+		for range tt.fn(data, pivot) {
+			if n++; n == 2 {
+				break
+			}
+		}
+	}
+
+	if got := pairs(join.Full(data[:0], 0)); got != "" {
+		t.Errorf("Full of empty relations = %s", got)
+	}
+}
+
+// nested joins the relations of data the slow way, one key at a time.
+func nested(data sort.IntSlice, pivot int, left, matched, right bool) string {
+	keys := append(sort.IntSlice(nil), data...)
+	sort.Sort(keys)
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 && keys[i-1] == k {
+			continue
+		}
+		var l, r []int
+		for x, v := range data {
+			if v == k && x < pivot {
+				l = append(l, x)
+			} else if v == k {
+				r = append(r, x)
+			}
+		}
+		switch {
+		case len(r) == 0 && left:
+			for _, x := range l {
+				fmt.Fprintf(&b, "(%d,-1)", x)
+			}
+		case len(l) == 0 && right:
+			for _, y := range r {
+				fmt.Fprintf(&b, "(-1,%d)", y)
+			}
+		case len(l) > 0 && len(r) > 0 && matched:
+			for _, x := range l {
+				for _, y := range r {
+					fmt.Fprintf(&b, "(%d,%d)", x, y)
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+func TestJoinRand(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	relation := func() []int {
+		s := make([]int, rng.Intn(20))
+		for i := range s {
+			s[i] = rng.Intn(12)
+		}
+		sort.Ints(s)
+		return s
+	}
+
+	tests := []struct {
+		name                 string
+		fn                   func(sort.Interface, int) iter.Seq2[int, int]
+		left, matched, right bool
+	}{
+		{"Inner", join.Inner, false, true, false},
+		{"Left", join.Left, true, true, false},
+		{"Right", join.Right, false, true, true},
+		{"Full", join.Full, true, true, true},
+		{"Anti", join.Anti, true, false, false},
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := relation(), relation()
+		data := append(sort.IntSlice(a), b...)
+		for _, tt := range tests {
+			want := nested(data, len(a), tt.left, tt.matched, tt.right)
+			if got := pairs(tt.fn(data, len(a))); got != want {
+				t.Fatalf("%s(%v, %v) = %s, want %s", tt.name, a, b, got, want)
+			}
+		}
+	}
+}
+
+type row struct {
+	id   int
+	name string
+}
+
+type rows []row
+
+func (r rows) Len() int           { return len(r) }
+func (r rows) Less(i, j int) bool { return r[i].id < r[j].id }
+func (r rows) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+func ExampleLeft() {
+	users := rows{{1, "ann"}, {2, "bob"}, {3, "cy"}}
+	orders := rows{{1, "book"}, {1, "pen"}, {3, "mug"}}
+
+	data := append(users, orders...)
+	for i, j := range join.Left(data, len(users)) {
+		if j < 0 {
+			fmt.Println(data[i].name, "-")
+			continue
+		}
+		fmt.Println(data[i].name, data[j].name)
+	}
+
+	// Output:
+	// ann book
+	// ann pen
+	// bob -
+	// cy mug
+}
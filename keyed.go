@@ -0,0 +1,68 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import "sort"
+
+// The Combine type represents a callback used by keyed merges, such as
+// UnionWith. It is called with the index, dst, of an element in [0:pivot],
+// and the index, src, of an equal element in [pivot:Len] that is about to
+// be discarded, so that the payload of src can be folded into dst (for
+// example by summing counters or keeping the newer of two timestamps).
+// Indices are as of the time of the call. Combine must not alter the
+// ordering of dst relative to other elements.
+type Combine func(dst, src int)
+
+// UnionWith is like Union, but calls combine for each pair of equal
+// elements, rather than arbitrarily keeping one of them.
+func UnionWith(data sort.Interface, pivot int, combine Combine) (size int) {
+	k, l := pivot, data.Len()
+	i, j, q := 0, k, k
+	a, b := 0, 0 // lengths of the current runs through each set
+
+	// compact the elements unique to [pivot:Len] toward its start
+	for i < k && j < l {
+		switch {
+		case data.Less(i, j):
+			i, a, b = advance(data, j, i, k, a), a+1, 0
+		case data.Less(j, i):
+			n := advance(data, i, j, l, b)
+			q = keep(data, q, j, n-j)
+			j, a, b = n, 0, b+1
+		default:
+			combine(i, j)
+			i, j = i+1, j+1
+			a, b = 0, 0
+		}
+	}
+	n := keep(data, q, j, l-j) - k
+
+	merge(data, 0, k, k+n)
+	return k + n
+}
+
+// InterWith is like Inter, but calls combine for each pair of equal
+// elements, rather than arbitrarily keeping one of them.
+func InterWith(data sort.Interface, pivot int, combine Combine) (size int) {
+	k, l := pivot, data.Len()
+	p, i, j := 0, 0, k
+	a, b := 0, 0 // lengths of the current runs through each set
+	for i < k && j < l {
+		switch {
+		case data.Less(i, j):
+			i, a, b = advance(data, j, i, k, a), a+1, 0
+		case data.Less(j, i):
+			j, a, b = advance(data, i, j, l, b), 0, b+1
+		default:
+			combine(i, j)
+			if p < i {
+				data.Swap(p, i)
+			}
+			p, i, j = p+1, i+1, j+1
+			a, b = 0, 0
+		}
+	}
+	return p
+}
@@ -0,0 +1,163 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+type counter struct {
+	key string
+	n   int
+}
+
+type counters []counter
+
+func (c counters) Len() int           { return len(c) }
+func (c counters) Less(i, j int) bool { return c[i].key < c[j].key }
+func (c counters) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+func (c counters) sum(dst, src int) { c[dst].n += c[src].n }
+
+func TestKeyed(t *testing.T) {
+	a := counters{{"a", 1}, {"b", 2}, {"d", 4}}
+	b := counters{{"b", 20}, {"c", 30}, {"d", 40}}
+
+	tests := []struct {
+		name string
+		op   func(data counters, pivot int) int
+		want string
+	}{
+		{"UnionWith", func(c counters, p int) int { return set.UnionWith(c, p, c.sum) }, "[{a 1} {b 22} {c 30} {d 44}]"},
+		{"InterWith", func(c counters, p int) int { return set.InterWith(c, p, c.sum) }, "[{b 22} {d 44}]"},
+	}
+
+	for _, tt := range tests {
+		data := append(append(counters(nil), a...), b...)
+		got := fmt.Sprint(data[:tt.op(data, len(a))])
+
+		if got != tt.want {
+			t.Errorf(format, tt.name, a, b, got, tt.want)
+		}
+	}
+}
+
+// lessCounter counts the comparisons made while merging counters.
+type lessCounter struct {
+	counters
+	n *int
+}
+
+func (c lessCounter) Less(i, j int) bool {
+	*c.n++
+	return c.counters.Less(i, j)
+}
+
+func TestKeyedRand(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(data sort.Interface, pivot int, combine set.Combine) int
+		ref  set.Op
+	}{
+		{"UnionWith", set.UnionWith, set.Union},
+		{"InterWith", set.InterWith, set.Inter},
+	}
+
+	type pair struct{ a, b []int }
+	var inputs []pair
+	for _, tt := range testdata.BinTests {
+		inputs = append(inputs, pair{tt.A, tt.B})
+	}
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 500; i++ {
+		inputs = append(inputs, pair{testdata.RandSet(rng), testdata.RandSet(rng)})
+	}
+
+	// a small set spread across a large one is galloped through, taking
+	// far fewer comparisons than there are elements
+	skew := testdata.Skew(testdata.Small, 4096)
+	gallops := map[int]bool{len(inputs): true, len(inputs) + 1: true}
+	inputs = append(inputs, pair{skew[0], skew[1]}, pair{skew[1], skew[0]})
+
+	key := func(x int) string { return fmt.Sprintf("%05d", x) }
+	index := func(s []int, off int) map[string]int {
+		m := map[string]int{}
+		for i, x := range s {
+			m[key(x)] = off + i
+		}
+		return m
+	}
+
+	for _, tt := range tests {
+		for n, in := range inputs {
+			a, b := in.a, in.b
+			ia, ib := index(a, 0), index(b, len(a))
+
+			// elements of a count 1, and those of b count 10
+			data := make(counters, 0, len(a)+len(b))
+			for _, x := range a {
+				data = append(data, counter{key(x), 1})
+			}
+			for _, x := range b {
+				data = append(data, counter{key(x), 10})
+			}
+
+			calls := map[string]int{}
+			combine := func(dst, src int) {
+				k := data[dst].key
+				if data[src].key != k || ia[k] != dst || ib[k] != src {
+					t.Fatalf("%s(%v, %v) combined %d and %d", tt.name, a, b, dst, src)
+				}
+				calls[k]++
+				data.sum(dst, src)
+			}
+
+			less := 0
+			size := tt.op(lessCounter{data, &less}, len(a), combine)
+
+			var want counters
+			for _, x := range set.Do(tt.ref, append([]int(nil), a...), b...) {
+				k := key(x)
+				_, inA := ia[k]
+				_, inB := ib[k]
+				c := counter{k, 0}
+				if inA {
+					c.n++
+				}
+				if inB {
+					c.n += 10
+				}
+				if inA && inB && calls[k] != 1 {
+					t.Fatalf("%s(%v, %v) combined key %d %d times", tt.name, a, b, x, calls[k])
+				}
+				want = append(want, c)
+			}
+			if got := data[:size]; fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Fatalf(format, tt.name, a, b, got, want)
+			}
+			if gallops[n] && less > (len(a)+len(b))/2 {
+				t.Errorf("%s of %d and %d elements made %d comparisons", tt.name, len(a), len(b), less)
+			}
+		}
+	}
+}
+
+func ExampleUnionWith() {
+	data := counters{{"apples", 3}, {"pears", 1}} // first set of counters
+	pivot := len(data)
+
+	data = append(data, counter{"kiwis", 2}, counter{"pears", 4}) // second set
+	size := set.UnionWith(data, pivot, data.sum)                  // sum counters with equal keys
+
+	fmt.Println(data[:size])
+
+	// Output: [{apples 3} {kiwis 2} {pears 5}]
+}
@@ -0,0 +1,122 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+)
+
+// Index returns the index of the element in the set [0:pivot] equal to the
+// probe element at index pivot, which is typically appended to the set just
+// before the call. As with the other lookup functions (Floor, Ceil, Pred and
+// Succ), the result is an index into [0:pivot], or -1 if no such element
+// exists, and Less is called O(log(pivot)) times.
+func Index(data sort.Interface, pivot int) int {
+	i := find(data, pivot, 0, pivot)
+	if i < pivot && !data.Less(pivot, i) {
+		return i
+	}
+	return -1
+}
+
+// Contains returns true if the probe is an element of the set.
+func Contains(data sort.Interface, pivot int) bool {
+	return Index(data, pivot) >= 0
+}
+
+// Floor returns the index of the greatest element not greater than the
+// probe.
+func Floor(data sort.Interface, pivot int) int {
+	return findAfter(data, pivot, 0, pivot) - 1
+}
+
+// Ceil returns the index of the least element not less than the probe.
+func Ceil(data sort.Interface, pivot int) int {
+	return within(find(data, pivot, 0, pivot), pivot)
+}
+
+// Pred returns the index of the greatest element less than the probe.
+func Pred(data sort.Interface, pivot int) int {
+	return find(data, pivot, 0, pivot) - 1
+}
+
+// Succ returns the index of the least element greater than the probe.
+func Succ(data sort.Interface, pivot int) int {
+	return within(findAfter(data, pivot, 0, pivot), pivot)
+}
+
+// Range returns the bounds of the elements in [0:pivot] that are not less
+// than the probe at index pivot, and less than the probe at index pivot+1,
+// such that [i:j] holds the elements in the half-open interval [lo, hi).
+// When hi is not greater than lo, i and j will be equal.
+func Range(data sort.Interface, pivot int) (i, j int) {
+	i = find(data, pivot, 0, pivot)
+	j = find(data, pivot+1, i, pivot)
+	return i, j
+}
+
+func within(i, n int) int {
+	if i < n {
+		return i
+	}
+	return -1
+}
+
+// SliceIndex returns the index of x in the set s, or -1 if x is not
+// present.
+func SliceIndex[T cmp.Ordered](s []T, x T) int {
+	i, ok := slices.BinarySearch(s, x)
+	if ok {
+		return i
+	}
+	return -1
+}
+
+// SliceContains returns true if x is an element of the set s.
+func SliceContains[T cmp.Ordered](s []T, x T) bool {
+	_, ok := slices.BinarySearch(s, x)
+	return ok
+}
+
+// SliceFloor returns the index of the greatest element in s not greater
+// than x, or -1.
+func SliceFloor[T cmp.Ordered](s []T, x T) int {
+	return after(s, x) - 1
+}
+
+// SliceCeil returns the index of the least element in s not less than x,
+// or -1.
+func SliceCeil[T cmp.Ordered](s []T, x T) int {
+	i, _ := slices.BinarySearch(s, x)
+	return within(i, len(s))
+}
+
+// SlicePred returns the index of the greatest element in s less than x,
+// or -1.
+func SlicePred[T cmp.Ordered](s []T, x T) int {
+	i, _ := slices.BinarySearch(s, x)
+	return i - 1
+}
+
+// SliceSucc returns the index of the least element in s greater than x,
+// or -1.
+func SliceSucc[T cmp.Ordered](s []T, x T) int {
+	return within(after(s, x), len(s))
+}
+
+// SliceRange returns the sub-slice of s holding the elements in the
+// half-open interval [lo, hi). The result shares s's backing array.
+func SliceRange[T cmp.Ordered](s []T, lo, hi T) []T {
+	i, _ := slices.BinarySearch(s, lo)
+	j, _ := slices.BinarySearch(s[i:], hi)
+	return s[i : i+j]
+}
+
+// after returns the index of the least element in s greater than x.
+func after[T cmp.Ordered](s []T, x T) int {
+	return sort.Search(len(s), func(i int) bool { return cmp.Less(x, s[i]) })
+}
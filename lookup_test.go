@@ -0,0 +1,102 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/xtgo/set"
+)
+
+var lookupTests = []struct {
+	x                              int
+	index, floor, ceil, pred, succ int
+}{
+	{x: 5, index: -1, floor: -1, ceil: 0, pred: -1, succ: 0},
+	{x: 10, index: 0, floor: 0, ceil: 0, pred: -1, succ: 1},
+	{x: 15, index: -1, floor: 0, ceil: 1, pred: 0, succ: 1},
+	{x: 20, index: 1, floor: 1, ceil: 1, pred: 0, succ: 2},
+	{x: 30, index: 2, floor: 2, ceil: 2, pred: 1, succ: -1},
+	{x: 35, index: -1, floor: 2, ceil: -1, pred: 2, succ: -1},
+}
+
+func TestLookup(t *testing.T) {
+	s := []int{10, 20, 30}
+	pivot := len(s)
+
+	type lookup struct {
+		name    string
+		fn      func(sort.Interface, int) int
+		slicefn func([]int, int) int
+		want    int
+	}
+
+	for _, tt := range lookupTests {
+		data := sort.IntSlice(append(s[:pivot:pivot], tt.x))
+
+		lookups := []lookup{
+			{"Index", set.Index, set.SliceIndex[int], tt.index},
+			{"Floor", set.Floor, set.SliceFloor[int], tt.floor},
+			{"Ceil", set.Ceil, set.SliceCeil[int], tt.ceil},
+			{"Pred", set.Pred, set.SlicePred[int], tt.pred},
+			{"Succ", set.Succ, set.SliceSucc[int], tt.succ},
+		}
+
+		for _, l := range lookups {
+			if i := l.fn(data, pivot); i != l.want {
+				t.Errorf("%s(%v, %d) = %d, want %d", l.name, s, tt.x, i, l.want)
+			}
+			if i := l.slicefn(s, tt.x); i != l.want {
+				t.Errorf("Slice%s(%v, %d) = %d, want %d", l.name, s, tt.x, i, l.want)
+			}
+		}
+
+		if ok := set.Contains(data, pivot); ok != (tt.index >= 0) {
+			t.Errorf("Contains(%v, %d) = %t", s, tt.x, ok)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	s := []int{10, 20, 30, 40}
+	pivot := len(s)
+
+	tests := []struct{ lo, hi, i, j int }{
+		{0, 5, 0, 0},
+		{0, 10, 0, 0},
+		{0, 11, 0, 1},
+		{10, 40, 0, 3},
+		{15, 45, 1, 4},
+		{30, 20, 2, 2},
+	}
+
+	for _, tt := range tests {
+		data := sort.IntSlice(append(s[:pivot:pivot], tt.lo, tt.hi))
+		i, j := set.Range(data, pivot)
+
+		if i != tt.i || j != tt.j {
+			t.Errorf("Range(%v, %d, %d) = %d, %d, want %d, %d", s, tt.lo, tt.hi, i, j, tt.i, tt.j)
+		}
+
+		r := set.SliceRange(s, tt.lo, tt.hi)
+		if want := s[tt.i:tt.j]; fmt.Sprint(r) != fmt.Sprint(want) {
+			t.Errorf("SliceRange(%v, %d, %d) = %v, want %v", s, tt.lo, tt.hi, r, want)
+		}
+	}
+}
+
+func ExampleFloor() {
+	data := sort.StringSlice{"apple", "kiwi", "pear"} // create a set (it must be sorted)
+	pivot := len(data)                                // store the length of our set
+
+	data = append(data, "orange") // append the value to search for
+	i := set.Floor(data, pivot)   // find the greatest element not after it
+
+	fmt.Println(data[i])
+
+	// Output: kiwi
+}
@@ -0,0 +1,105 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"math"
+	"sort"
+)
+
+// InterLen returns the number of elements in both of the sets [0:pivot]
+// and [pivot:Len], which is the size Inter would return. data is not
+// modified.
+func InterLen(data sort.Interface, pivot int) int {
+	if debug {
+		assertIn("InterLen", data, pivot)
+	}
+	i, j, k, l := 0, pivot, pivot, data.Len()
+	a, b := 0, 0 // lengths of the current runs through each set
+	n := 0
+	for i < k && j < l {
+		switch {
+		case data.Less(i, j):
+			i, a, b = advance(data, j, i, k, a), a+1, 0
+		case data.Less(j, i):
+			j, a, b = advance(data, i, j, l, b), 0, b+1
+		default:
+			i, j, n = i+1, j+1, n+1
+			a, b = 0, 0
+		}
+	}
+	return n
+}
+
+// UnionLen returns the size Union would return, without modifying data.
+func UnionLen(data sort.Interface, pivot int) int {
+	return data.Len() - InterLen(data, pivot)
+}
+
+// DiffLen returns the size Diff would return, without modifying data.
+func DiffLen(data sort.Interface, pivot int) int {
+	return pivot - InterLen(data, pivot)
+}
+
+// SymDiffLen returns the size SymDiff would return, without modifying
+// data.
+func SymDiffLen(data sort.Interface, pivot int) int {
+	return data.Len() - 2*InterLen(data, pivot)
+}
+
+// The similarity measures below compare the sets [0:pivot] and
+// [pivot:Len] without modifying data, returning a value from 0, when the
+// sets are disjoint, to 1, when they are equal. Two empty sets are
+// considered equal.
+
+// Jaccard returns the Jaccard index of the sets [0:pivot] and [pivot:Len]:
+// the size of their intersection divided by the size of their union.
+func Jaccard(data sort.Interface, pivot int) float64 {
+	n := InterLen(data, pivot)
+	return ratio(float64(n), float64(data.Len()-n))
+}
+
+// Dice returns the Sørensen-Dice coefficient of the sets [0:pivot] and
+// [pivot:Len]: twice the size of their intersection divided by the sum of
+// their sizes.
+func Dice(data sort.Interface, pivot int) float64 {
+	n := InterLen(data, pivot)
+	return ratio(float64(2*n), float64(data.Len()))
+}
+
+// Overlap returns the overlap (Szymkiewicz-Simpson) coefficient of the
+// sets [0:pivot] and [pivot:Len]: the size of their intersection divided
+// by the size of the smaller set. Overlap returns 1 whenever one set is a
+// subset of the other, except that an empty set only overlaps another
+// empty set.
+func Overlap(data sort.Interface, pivot int) float64 {
+	n := InterLen(data, pivot)
+	m := min(pivot, data.Len()-pivot)
+	if m == 0 && data.Len() > 0 {
+		return 0
+	}
+	return ratio(float64(n), float64(m))
+}
+
+// Cosine returns the cosine similarity (Ochiai coefficient) of the sets
+// [0:pivot] and [pivot:Len]: the size of their intersection divided by the
+// geometric mean of their sizes.
+func Cosine(data sort.Interface, pivot int) float64 {
+	n := InterLen(data, pivot)
+	m := math.Sqrt(float64(pivot) * float64(data.Len()-pivot))
+	if m == 0 && data.Len() > 0 {
+		return 0
+	}
+	return ratio(float64(n), m)
+}
+
+// ratio returns n/d, or 1 if d is zero, which only occurs when both sets
+// are empty.
+func ratio(n, d float64) float64 {
+	if d == 0 {
+		return 1
+	}
+	return n / d
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+func TestLen(t *testing.T) {
+	lens := map[string]func(sort.Interface, int) int{
+		"Union":   set.UnionLen,
+		"Inter":   set.InterLen,
+		"Diff":    set.DiffLen,
+		"SymDiff": set.SymDiffLen,
+	}
+
+	for name, fn := range lens {
+		for _, tt := range testdata.BinTests {
+			data := append(append(sort.IntSlice(nil), tt.A...), tt.B...)
+			n := fn(data, len(tt.A))
+			want := len(tt.SelSlice(name))
+
+			if n != want {
+				t.Errorf(format, name+"Len", tt.A, tt.B, n, want)
+			}
+			if !testdata.IsEqual(data, append(append([]int(nil), tt.A...), tt.B...)) {
+				t.Errorf("%sLen(%v, %v) modified data to %v", name, tt.A, tt.B, data)
+			}
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	type sim = func(sort.Interface, int) float64
+	tests := []struct {
+		a, b                 []int
+		jacc, dice, ov, cosv float64
+	}{
+		{nil, nil, 1, 1, 1, 1},
+		{nil, []int{1}, 0, 0, 0, 0},
+		{[]int{1, 2}, []int{3, 4}, 0, 0, 0, 0},
+		{[]int{1, 2, 3}, []int{1, 2, 3}, 1, 1, 1, 1},
+		{[]int{1, 2}, []int{1, 2, 3, 4}, 0.5, 2.0 / 3, 1, 1 / math.Sqrt(2)},
+		{[]int{1, 2, 3}, []int{2, 3, 4}, 0.5, 2.0 / 3, 2.0 / 3, 2.0 / 3},
+	}
+
+	for _, tt := range tests {
+		data := append(append(sort.IntSlice(nil), tt.a...), tt.b...)
+		for _, c := range []struct {
+			name string
+			fn   sim
+			want float64
+		}{
+			{"Jaccard", set.Jaccard, tt.jacc},
+			{"Dice", set.Dice, tt.dice},
+			{"Overlap", set.Overlap, tt.ov},
+			{"Cosine", set.Cosine, tt.cosv},
+		} {
+			got := c.fn(data, len(tt.a))
+			if math.Abs(got-c.want) > 1e-12 {
+				t.Errorf(format, c.name, tt.a, tt.b, got, c.want)
+			}
+		}
+	}
+}
+
+func ExampleJaccard() {
+	a := []int{1, 2, 3, 4}
+	b := []int{3, 4, 5, 6}
+
+	data := append(append(sort.IntSlice(nil), a...), b...)
+	fmt.Println(set.InterLen(data, len(a)), set.UnionLen(data, len(a)))
+	fmt.Printf("%.3f\n", set.Jaccard(data, len(a)))
+
+	// Output:
+	// 2 6
+	// 0.333
+}
@@ -0,0 +1,146 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"cmp"
+	"iter"
+	"sort"
+)
+
+// BagUnion performs an in-place multiset union: each element occurs in
+// the result as many times as it does in whichever input has more copies.
+// BagUnion is both associative and commutative.
+func BagUnion(data sort.Interface, pivot int) (size int) {
+	k, l := pivot, data.Len()
+	i, j, q := 0, k, k
+	a, b := 0, 0 // lengths of the current runs through each set
+
+	// compact the copies of [pivot:Len] in excess of [0:pivot]
+	for i < k && j < l {
+		switch {
+		case data.Less(i, j):
+			i, a, b = advance(data, j, i, k, a), a+1, 0
+		case data.Less(j, i):
+			n := advance(data, i, j, l, b)
+			q = keep(data, q, j, n-j)
+			j, a, b = n, 0, b+1
+		default:
+			m, n := runEnd(data, i, k), runEnd(data, j, l)
+			q = keep(data, q, j, max(n-j-(m-i), 0))
+			i, j = m, n
+			a, b = 0, 0
+		}
+	}
+	n := keep(data, q, j, l-j) - k
+
+	merge(data, 0, k, k+n)
+	return k + n
+}
+
+// BagSum performs an in-place multiset sum: each element occurs in the
+// result as many times as it does in both inputs combined. BagSum is both
+// associative and commutative.
+func BagSum(data sort.Interface, pivot int) (size int) {
+	l := data.Len()
+	merge(data, 0, pivot, l)
+	return l
+}
+
+// BagInter performs an in-place multiset intersection: each element occurs
+// in the result as many times as it does in whichever input has fewer
+// copies. BagInter is both associative and commutative.
+func BagInter(data sort.Interface, pivot int) (size int) {
+	k, l := pivot, data.Len()
+	p, i, j := 0, 0, k
+	a, b := 0, 0 // lengths of the current runs through each set
+	for i < k && j < l {
+		switch {
+		case data.Less(i, j):
+			i, a, b = advance(data, j, i, k, a), a+1, 0
+		case data.Less(j, i):
+			j, a, b = advance(data, i, j, l, b), 0, b+1
+		default:
+			m, n := runEnd(data, i, k), runEnd(data, j, l)
+			p = keep(data, p, i, min(m-i, n-j))
+			i, j = m, n
+			a, b = 0, 0
+		}
+	}
+	return p
+}
+
+// BagDiff performs an in-place multiset difference: each element occurs in
+// the result as many times as it does in [0:pivot], less the number of
+// times it does in [pivot:Len], if positive. BagDiff is neither
+// associative nor commutative.
+func BagDiff(data sort.Interface, pivot int) (size int) {
+	k, l := pivot, data.Len()
+	p, i, j := 0, 0, k
+	a, b := 0, 0 // lengths of the current runs through each set
+	for i < k && j < l {
+		switch {
+		case data.Less(i, j):
+			n := advance(data, j, i, k, a)
+			p = keep(data, p, i, n-i)
+			i, a, b = n, a+1, 0
+		case data.Less(j, i):
+			j, a, b = advance(data, i, j, l, b), 0, b+1
+		default:
+			m, n := runEnd(data, i, k), runEnd(data, j, l)
+			p = keep(data, p, i, max(m-i-(n-j), 0))
+			i, j = m, n
+			a, b = 0, 0
+		}
+	}
+	return keep(data, p, i, k-i)
+}
+
+// Runs returns an iterator over the runs of equal elements in the sorted
+// data, yielding the index of the first element of each run, and the
+// number of elements in the run.
+func Runs(data sort.Interface) iter.Seq2[int, int] {
+	return func(yield func(i, n int) bool) {
+		l := data.Len()
+		for i := 0; i < l; {
+			j := runEnd(data, i, l)
+			if !yield(i, j-i) {
+				return
+			}
+			i = j
+		}
+	}
+}
+
+// SliceRuns returns an iterator over the distinct elements of the sorted
+// slice s, yielding each along with the number of times it occurs.
+func SliceRuns[T cmp.Ordered](s []T) iter.Seq2[T, int] {
+	return func(yield func(x T, n int) bool) {
+		for i, n := range Runs(ordered[T](s)) {
+			if !yield(s[i], n) {
+				return
+			}
+		}
+	}
+}
+
+// runEnd returns the index following the run of elements in [i:j] equal to
+// the element at i.
+func runEnd(data sort.Interface, i, j int) int {
+	n := i + 1
+	for n < j && !data.Less(i, n) {
+		n++
+	}
+	return n
+}
+
+// keep moves the n elements at [i:i+n] to [p:p+n], where p <= i, returning
+// p+n.
+func keep(data sort.Interface, p, i, n int) int {
+	if p < i {
+		return xcopy(data, p, i, p+n, i+n)
+	}
+	return p + n
+}
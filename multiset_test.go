@@ -0,0 +1,80 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+func TestBag(t *testing.T) {
+	tasks := []struct {
+		name  string
+		op    set.Op
+		count func(a, b int) int
+	}{
+		{"BagUnion", set.BagUnion, func(a, b int) int { return max(a, b) }},
+		{"BagSum", set.BagSum, func(a, b int) int { return a + b }},
+		{"BagInter", set.BagInter, func(a, b int) int { return min(a, b) }},
+		{"BagDiff", set.BagDiff, func(a, b int) int { return max(a-b, 0) }},
+	}
+
+	rng := rand.New(rand.NewSource(0))
+	randBag := func() []int {
+		s := make([]int, rng.Intn(48))
+		for i := range s {
+			s[i] = rng.Intn(16)
+		}
+		sort.Ints(s)
+		return s
+	}
+
+	counts := func(s []int) map[int]int {
+		m := map[int]int{}
+		for x, n := range set.SliceRuns(s) {
+			m[x] = n
+		}
+		return m
+	}
+
+	for _, task := range tasks {
+		for i := 0; i < 500; i++ {
+			a, b := randBag(), randBag()
+			ca, cb := counts(a), counts(b)
+
+			var want []int
+			for x := 0; x < 16; x++ {
+				for n := task.count(ca[x], cb[x]); n > 0; n-- {
+					want = append(want, x)
+				}
+			}
+
+			data := sort.IntSlice(append(append([]int(nil), a...), b...))
+			got := data[:task.op(data, len(a))]
+
+			if !testdata.IsEqual(got, want) {
+				t.Fatalf(format, task.name, a, b, got, want)
+			}
+		}
+	}
+}
+
+func ExampleRuns() {
+	data := sort.StringSlice{"a", "a", "b", "c", "c", "c"}
+
+	for i, n := range set.Runs(data) {
+		fmt.Println(data[i], n)
+	}
+
+	// Output:
+	// a 2
+	// b 1
+	// c 3
+}
@@ -15,6 +15,10 @@ type Op func(data sort.Interface, pivot int) (size int)
 // the range [0:size] will remain in sorted order. Uniq, following a
 // sort.Sort call, can be used to prepare arbitrary inputs for use as sets.
 func Uniq(data sort.Interface) (size int) {
+	if debug {
+		assertSorted("Uniq", data)
+		defer func() { assertOut("Uniq", data, size, data.Len()) }()
+	}
 	p, l := 0, data.Len()
 	if l <= 1 {
 		return l
@@ -35,19 +39,25 @@ func Uniq(data sort.Interface) (size int) {
 // [pivot:Len]; the resulting set will occupy [0:size]. Inter is both
 // associative and commutative.
 func Inter(data sort.Interface, pivot int) (size int) {
+	if debug {
+		assertIn("Inter", data, pivot)
+		defer func() { assertOut("Inter", data, size, min(pivot, data.Len()-pivot)) }()
+	}
 	k, l := pivot, data.Len()
 	p, i, j := 0, 0, k
+	a, b := 0, 0 // lengths of the current runs through each set
 	for i < k && j < l {
 		switch {
 		case data.Less(i, j):
-			i++
+			i, a, b = advance(data, j, i, k, a), a+1, 0
 		case data.Less(j, i):
-			j++
+			j, a, b = advance(data, i, j, l, b), 0, b+1
 		case p < i:
 			data.Swap(p, i)
 			fallthrough
 		default:
 			p, i, j = p+1, i+1, j+1
+			a, b = 0, 0
 		}
 	}
 	return p
@@ -57,9 +67,11 @@ func Inter(data sort.Interface, pivot int) (size int) {
 // [pivot:Len]; the resulting set will occupy [0:size]. Union is both
 // associative and commutative.
 func Union(data sort.Interface, pivot int) (size int) {
-	// BUG(extemporalgenome): Union currently uses a multi-pass implementation
-
-	sort.Sort(data)
+	if debug {
+		assertIn("Union", data, pivot)
+		defer func() { assertOut("Union", data, size, data.Len()) }()
+	}
+	merge(data, 0, pivot, data.Len())
 	return Uniq(data)
 }
 
@@ -67,19 +79,28 @@ func Union(data sort.Interface, pivot int) (size int) {
 // [pivot:Len]; the resulting set will occupy [0:size]. Diff is neither
 // associative nor commutative.
 func Diff(data sort.Interface, pivot int) (size int) {
+	if debug {
+		assertIn("Diff", data, pivot)
+		defer func() { assertOut("Diff", data, size, pivot) }()
+	}
 	k, l := pivot, data.Len()
 	p, i, j := 0, 0, k
+	a, b := 0, 0 // lengths of the current runs through each set
 	for i < k && j < l {
 		switch {
 		case data.Less(i, j):
+			n := advance(data, j, i, k, a)
 			if p < i {
-				data.Swap(p, i)
+				p = xcopy(data, p, i, k, n)
+			} else {
+				p = n
 			}
-			p, i = p+1, i+1
+			i, a, b = n, a+1, 0
 		case data.Less(j, i):
-			j++
+			j, a, b = advance(data, i, j, l, b), 0, b+1
 		default:
 			i, j = i+1, j+1
+			a, b = 0, 0
 		}
 	}
 	return xcopy(data, p, i, k, k)
@@ -89,15 +110,43 @@ func Diff(data sort.Interface, pivot int) (size int) {
 // [0:pivot] and [pivot:Len]; the resulting set will occupy [0:size].
 // SymDiff is both associative and commutative.
 func SymDiff(data sort.Interface, pivot int) (size int) {
-	// BUG(extemporalgenome): SymDiff currently uses a multi-pass implementation
+	if debug {
+		assertIn("SymDiff", data, pivot)
+		defer func() { assertOut("SymDiff", data, size, data.Len()) }()
+	}
+	k, l := pivot, data.Len()
+	p, i, j, q := 0, 0, k, k
+	a, b := 0, 0 // lengths of the current runs through each set
+
+	// compact the elements unique to each set toward the start of that set
+	for i < k && j < l {
+		switch {
+		case data.Less(i, j):
+			n := advance(data, j, i, k, a)
+			if p < i {
+				p = xcopy(data, p, i, k, n)
+			} else {
+				p = n
+			}
+			i, a, b = n, a+1, 0
+		case data.Less(j, i):
+			n := advance(data, i, j, l, b)
+			if q < j {
+				q = xcopy(data, q, j, l, n)
+			} else {
+				q = n
+			}
+			j, a, b = n, 0, b+1
+		default:
+			i, j = i+1, j+1
+			a, b = 0, 0
+		}
+	}
+	p = xcopy(data, p, i, k, k)
+	n := xcopy(data, q, j, l, l) - k
 
-	i := Inter(data, pivot)
-	l := data.Len()
-	b := boundspan{data, span{i, l}}
-	sort.Sort(b)
-	size = Uniq(b)
-	slide(data, 0, i, size)
-	l = i + size
-	sort.Sort(boundspan{data, span{size, l}})
-	return Diff(data, size)
+	// join the two remainders, then merge them (they are disjoint)
+	slide(data, p, k, n)
+	merge(data, 0, p, p+n)
+	return p + n
 }
@@ -0,0 +1,11 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !setdebug
+
+package set
+
+// debug is false unless the setdebug build tag is set, so the assertions
+// guarded by it are compiled away.
+const debug = false
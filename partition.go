@@ -0,0 +1,80 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import "sort"
+
+// Partition rearranges the two sets [0:pivot] and [pivot:Len] in place into
+// three adjacent sets: [0:i] holds the elements only in the first set (as
+// from Diff), [i:j] holds the elements in both (as from Inter), and
+// [j:size] holds the elements only in the second set, where size is
+// Len-(j-i). Elements moved into [size:Len] have undefined order.
+//
+// Partition is useful for reporting which elements were removed, kept and
+// added between two versions of a set, without copying the data and
+// running three separate ops.
+func Partition(data sort.Interface, pivot int) (i, j int) {
+	k, l := pivot, data.Len()
+	if debug {
+		assertIn("Partition", data, pivot)
+		defer func() { assertParts("Partition", data, i, j, l-(j-i)) }()
+	}
+
+	// split the second set into its common elements followed by its own,
+	// walking the first set in step to test for membership; elements of
+	// the first set passed over along the way are unique to it, and are
+	// compacted toward its start, as by Diff
+	p, c := 0, 0
+	in := func(x int) bool {
+		if c < k && data.Less(c, x) {
+			n := gallop(data, x, c+1, k)
+			p, c = keep(data, p, c, n-c), n
+		}
+		if c < k && !data.Less(x, c) {
+			c++
+			return true
+		}
+		return false
+	}
+	m := stablePartition(data, k, l, in)
+
+	// keep the rest of the first set, then close the gap
+	i = keep(data, p, c, k-c)
+	slide(data, i, k, l-k)
+	return i, i + m - k
+}
+
+// minPartition is the size of range below which stablePartition moves
+// elements individually rather than dividing the range.
+const minPartition = 16
+
+// stablePartition rearranges [i:j] so that the elements for which in
+// returns true precede those for which it returns false, preserving the
+// order within each group, and returns the index of the first element of
+// the second group. in is called once per element, in order of the
+// elements' original positions, and before the element is moved. Swap is
+// called O(n*log(n)) times.
+func stablePartition(data sort.Interface, i, j int, in func(x int) bool) int {
+	if j-i <= minPartition {
+		m := i
+		for x := i; x < j; x++ {
+			if !in(x) {
+				continue
+			}
+			for y := x; y > m; y-- {
+				data.Swap(y, y-1)
+			}
+			m++
+		}
+		return m
+	}
+	h := int(uint(i+j) >> 1)
+	a := stablePartition(data, i, h, in)
+	b := stablePartition(data, h, j, in)
+	if a < h && h < b {
+		rotate(data, a, h, b)
+	}
+	return a + b - h
+}
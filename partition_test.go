@@ -0,0 +1,64 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+func TestPartition(t *testing.T) {
+	type pair struct{ a, b []int }
+	var inputs []pair
+	for _, tt := range testdata.BinTests {
+		inputs = append(inputs, pair{tt.A, tt.B})
+	}
+
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 500; i++ {
+		inputs = append(inputs, pair{testdata.RandSet(rng), testdata.RandSet(rng)})
+	}
+
+	for _, in := range inputs {
+		a, b := in.a, in.b
+		data := append(append(sort.IntSlice(nil), a...), b...)
+		i, j := set.Partition(data, len(a))
+		size := len(data) - (j - i)
+
+		diff := set.Do(set.Diff, append([]int(nil), a...), b...)
+		inter := set.Do(set.Inter, append([]int(nil), a...), b...)
+		rdiff := set.Do(set.Diff, append([]int(nil), b...), a...)
+
+		if !testdata.IsEqual(data[:i], diff) ||
+			!testdata.IsEqual(data[i:j], inter) ||
+			!testdata.IsEqual(data[j:size], rdiff) {
+			t.Errorf("Partition(%v, %v) = %v | %v | %v, want %v | %v | %v",
+				a, b, data[:i], data[i:j], data[j:size], diff, inter, rdiff)
+		}
+	}
+}
+
+func ExamplePartition() {
+	before := []int{1, 2, 3, 5, 8}
+	after := []int{2, 3, 4, 8, 9}
+
+	data := append(append(sort.IntSlice(nil), before...), after...)
+	i, j := set.Partition(data, len(before))
+	size := len(data) - (j - i)
+
+	fmt.Println("removed:", data[:i])
+	fmt.Println("kept:   ", data[i:j])
+	fmt.Println("added:  ", data[j:size])
+
+	// Output:
+	// removed: [1 5]
+	// kept:    [2 3 8]
+	// added:   [4 9]
+}
@@ -18,10 +18,129 @@ func slide(data sort.Interface, i, j, n int) {
 	xcopy(data, i, j, i+n, j+n)
 }
 
-/*
+// minGallop is the number of consecutive steps through one set after which
+// ops switch from linear scanning to galloping, which keeps the overhead
+// of galloping away from inputs whose sets interleave finely.
+const minGallop = 4
+
+// advance returns the index following i, where run is the number of
+// consecutive steps already taken through i's set. Short runs are stepped
+// through linearly; longer runs gallop through [i+1:j] toward the first
+// element not less than the element at x.
+func advance(data sort.Interface, x, i, j, run int) int {
+	if run < minGallop {
+		return i + 1
+	}
+	return gallop(data, x, i+1, j)
+}
+
+// gallop returns the smallest index in [i:j] whose element is not less
+// than the element at x. Indices are probed at exponentially increasing
+// offsets from i before narrowing with a binary search, so finding index n
+// takes O(log(n-i)) calls to Less.
+func gallop(data sort.Interface, x, i, j int) int {
+	lo, step := i, 1
+	for i < j && data.Less(i, x) {
+		lo = i + 1
+		i += step
+		step *= 2
+	}
+	if i > j {
+		i = j
+	}
+	for lo < i {
+		h := int(uint(lo+i) >> 1)
+		if data.Less(h, x) {
+			lo = h + 1
+		} else {
+			i = h
+		}
+	}
+	return lo
+}
+
+// rotate swaps the adjacent ranges [i:j] and [j:k] in place, using at
+// most k-i swaps.
+func rotate(data sort.Interface, i, j, k int) {
+	m, n := j-i, k-j
+	for m != n {
+		if m > n {
+			slide(data, j-m, j, n)
+			m -= n
+		} else {
+			slide(data, j-m, j+n-m, m)
+			n -= m
+		}
+	}
+	slide(data, j-m, j, m)
+}
+
+// merge stably merges the sorted ranges [i:j] and [j:k] in place, using
+// the SymMerge algorithm of Kim and Kutzner. Less is called
+// O(m*log(n/m+1)) times, where m is the size of the smaller range and n
+// the size of the larger.
+func merge(data sort.Interface, i, j, k int) {
+	switch {
+	case i == j || j == k || !data.Less(j, j-1):
+		// at least one range is empty, or the ranges are already in order
+		return
+	case j-i == 1:
+		// insert data[i] into [j:k] before any equal elements
+		p := j + sort.Search(k-j, func(x int) bool { return !data.Less(j+x, i) })
+		for ; i < p-1; i++ {
+			data.Swap(i, i+1)
+		}
+		return
+	case k-j == 1:
+		// insert data[j] into [i:j] after any equal elements
+		p := i + sort.Search(j-i, func(x int) bool { return data.Less(j, i+x) })
+		for ; j > p; j-- {
+			data.Swap(j, j-1)
+		}
+		return
+	}
+
+	mid := int(uint(i+k) >> 1)
+	n := mid + j
+	var lo, hi int
+	if j > mid {
+		lo, hi = n-k, mid
+	} else {
+		lo, hi = i, j
+	}
+	p := n - 1
+	for lo < hi {
+		c := int(uint(lo+hi) >> 1)
+		if !data.Less(p-c, c) {
+			lo = c + 1
+		} else {
+			hi = c
+		}
+	}
+	end := n - lo
+	if lo < j && j < end {
+		rotate(data, lo, j, end)
+	}
+	if i < lo && lo < mid {
+		merge(data, i, lo, mid)
+	}
+	if mid < end && end < k {
+		merge(data, mid, end, k)
+	}
+}
+
+// find returns the smallest index in [i:j] whose element is not less than
+// the element at x.
 func find(data sort.Interface, x, i, j int) int {
-	return sort.Search(j-i, func(y int) bool {
-		return !data.Less(x, i+y)
+	return i + sort.Search(j-i, func(y int) bool {
+		return !data.Less(i+y, x)
+	})
+}
+
+// findAfter returns the smallest index in [i:j] whose element is greater
+// than the element at x.
+func findAfter(data sort.Interface, x, i, j int) int {
+	return i + sort.Search(j-i, func(y int) bool {
+		return data.Less(x, i+y)
 	})
 }
-*/
@@ -13,15 +13,19 @@ type Cmp func(data sort.Interface, pivot int) bool
 // IsSub returns true only if all elements in the range [0:pivot] are
 // also present in the range [pivot:Len].
 func IsSub(data sort.Interface, pivot int) bool {
+	if debug {
+		assertIn("IsSub", data, pivot)
+	}
 	i, j, k, l := 0, pivot, pivot, data.Len()
+	b := 0 // length of the current run through [pivot:Len]
 	for i < k && j < l {
 		switch {
 		case data.Less(i, j):
 			return false
 		case data.Less(j, i):
-			j++
+			j, b = advance(data, i, j, l, b), b+1
 		default:
-			i, j = i+1, j+1
+			i, j, b = i+1, j+1, 0
 		}
 	}
 	return i == k
@@ -31,15 +35,19 @@ func IsSub(data sort.Interface, pivot int) bool {
 // also present in the range [0:pivot]. IsSuper is especially useful for
 // full membership testing.
 func IsSuper(data sort.Interface, pivot int) bool {
+	if debug {
+		assertIn("IsSuper", data, pivot)
+	}
 	i, j, k, l := 0, pivot, pivot, data.Len()
+	a := 0 // length of the current run through [0:pivot]
 	for i < k && j < l {
 		switch {
 		case data.Less(i, j):
-			i++
+			i, a = advance(data, j, i, k, a), a+1
 		case data.Less(j, i):
 			return false
 		default:
-			i, j = i+1, j+1
+			i, j, a = i+1, j+1, 0
 		}
 	}
 	return j == l
@@ -49,13 +57,17 @@ func IsSuper(data sort.Interface, pivot int) bool {
 // present in the range [pivot:Len]. IsInter is especially useful for
 // partial membership testing.
 func IsInter(data sort.Interface, pivot int) bool {
+	if debug {
+		assertIn("IsInter", data, pivot)
+	}
 	i, j, k, l := 0, pivot, pivot, data.Len()
+	a, b := 0, 0 // lengths of the current runs through each set
 	for i < k && j < l {
 		switch {
 		case data.Less(i, j):
-			i++
+			i, a, b = advance(data, j, i, k, a), a+1, 0
 		case data.Less(j, i):
-			j++
+			j, a, b = advance(data, i, j, l, b), 0, b+1
 		default:
 			return true
 		}
@@ -65,6 +77,9 @@ func IsInter(data sort.Interface, pivot int) bool {
 
 // IsEqual returns true if the sets [0:pivot] and [pivot:Len] are equal.
 func IsEqual(data sort.Interface, pivot int) bool {
+	if debug {
+		assertIn("IsEqual", data, pivot)
+	}
 	k, l := pivot, data.Len()
 	if k*2 != l {
 		return false
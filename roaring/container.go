@@ -0,0 +1,455 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package roaring
+
+import (
+	"math/bits"
+	"slices"
+	"sort"
+
+	"github.com/xtgo/set"
+)
+
+// A container holds the low 16 bits of the elements sharing a chunk. Every
+// container is non-empty; the operations below return nil for an empty
+// result.
+type container interface {
+	card() int
+	contains(x uint16) bool
+
+	// add and remove return the updated container, which may have a
+	// different representation than the original.
+	add(x uint16) container
+	remove(x uint16) container
+
+	// each calls fn for each element in ascending order, stopping early
+	// and returning false if fn does.
+	each(fn func(x uint16) bool) bool
+
+	clone() container
+}
+
+const (
+	arrayMax   = 4096 // the most elements held by an array container
+	bitmapSize = 1 << 16 / 64
+)
+
+// op identifies a binary set operation.
+type op int
+
+const (
+	union op = iota
+	inter
+	diff
+	symDiff
+)
+
+// array is a sorted slice of elements, used for sparse chunks.
+type array []uint16
+
+func (a array) card() int { return len(a) }
+
+func (a array) contains(x uint16) bool { return set.SliceContains(a, x) }
+
+func (a array) add(x uint16) container {
+	a, ok := set.Insert(a, x)
+	if ok && len(a) > arrayMax {
+		return toBitmap(a)
+	}
+	return a
+}
+
+func (a array) remove(x uint16) container {
+	a, _ = set.Remove(a, x)
+	if len(a) == 0 {
+		return nil
+	}
+	return a
+}
+
+func (a array) each(fn func(x uint16) bool) bool {
+	for _, x := range a {
+		if !fn(x) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a array) clone() container { return append(array(nil), a...) }
+
+// filter returns the elements of a that are (or, if keep is false, are
+// not) contained in c.
+func (a array) filter(c container, keep bool) array {
+	var b array
+	for _, x := range a {
+		if c.contains(x) == keep {
+			b = append(b, x)
+		}
+	}
+	return b
+}
+
+// bitmap is a fixed-size bit vector, used for dense chunks.
+type bitmap struct {
+	words [bitmapSize]uint64
+	n     int // cardinality
+}
+
+func (b *bitmap) card() int { return b.n }
+
+func (b *bitmap) contains(x uint16) bool { return b.words[x/64]&(1<<(x%64)) != 0 }
+
+func (b *bitmap) add(x uint16) container {
+	if !b.contains(x) {
+		b.words[x/64] |= 1 << (x % 64)
+		b.n++
+	}
+	return b
+}
+
+func (b *bitmap) remove(x uint16) container {
+	if b.contains(x) {
+		b.words[x/64] &^= 1 << (x % 64)
+		b.n--
+	}
+	if b.n <= arrayMax {
+		return optimize(b)
+	}
+	return b
+}
+
+func (b *bitmap) each(fn func(x uint16) bool) bool {
+	for i, w := range b.words {
+		for w != 0 {
+			t := bits.TrailingZeros64(w)
+			if !fn(uint16(i*64 + t)) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+func (b *bitmap) clone() container {
+	c := *b
+	return &c
+}
+
+// combine returns the result of applying o to b and c, word by word.
+func (b *bitmap) combine(o op, c *bitmap) *bitmap {
+	d := new(bitmap)
+	for i := range d.words {
+		x, y := b.words[i], c.words[i]
+		switch o {
+		case union:
+			x |= y
+		case inter:
+			x &= y
+		case diff:
+			x &^= y
+		case symDiff:
+			x ^= y
+		}
+		d.words[i] = x
+		d.n += bits.OnesCount64(x)
+	}
+	return d
+}
+
+// setRange sets the bits in [lo, hi].
+func (b *bitmap) setRange(lo, hi int) {
+	for x := lo; x <= hi; x++ {
+		b.words[x/64] |= 1 << (x % 64)
+	}
+	b.n += hi - lo + 1
+}
+
+// runs is a sorted slice of disjoint, non-adjacent runs, used for chunks
+// made up of long stretches of consecutive elements.
+type runs []run
+
+// run represents the elements in the inclusive range [lo, hi].
+type run struct{ lo, hi uint16 }
+
+func (r runs) card() int {
+	n := 0
+	for _, v := range r {
+		n += int(v.hi-v.lo) + 1
+	}
+	return n
+}
+
+// search returns the index of the first run ending at or after x.
+func (r runs) search(x uint16) int {
+	return sort.Search(len(r), func(i int) bool { return r[i].hi >= x })
+}
+
+func (r runs) contains(x uint16) bool {
+	i := r.search(x)
+	return i < len(r) && r[i].lo <= x
+}
+
+// add extends, merges or inserts a run in place. Only a new run can push r
+// past the point where another representation is smaller.
+func (r runs) add(x uint16) container {
+	i := r.search(x)
+	if i < len(r) && r[i].lo <= x {
+		return r
+	}
+	before := i > 0 && r[i-1].hi+1 == x
+	after := i < len(r) && r[i].lo-1 == x
+	switch {
+	case before && after:
+		r[i-1].hi = r[i].hi
+		return slices.Delete(r, i, i+1)
+	case before:
+		r[i-1].hi = x
+		return r
+	case after:
+		r[i].lo = x
+		return r
+	}
+	return optimize(slices.Insert(r, i, run{x, x}))
+}
+
+// remove shrinks, splits or deletes the run holding x in place.
+func (r runs) remove(x uint16) container {
+	i := r.search(x)
+	if i == len(r) || r[i].lo > x {
+		return r
+	}
+	switch v := r[i]; {
+	case v.lo == v.hi:
+		r = slices.Delete(r, i, i+1)
+	case x == v.lo:
+		r[i].lo++
+	case x == v.hi:
+		r[i].hi--
+	default:
+		r[i].hi = x - 1
+		r = slices.Insert(r, i+1, run{x + 1, v.hi})
+	}
+	return optimize(r)
+}
+
+func (r runs) each(fn func(x uint16) bool) bool {
+	for _, v := range r {
+		for x := int(v.lo); x <= int(v.hi); x++ {
+			if !fn(uint16(x)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r runs) clone() container { return append(runs(nil), r...) }
+
+func (r runs) intervals() set.IntervalSet[int] {
+	s := make(set.IntervalSet[int], len(r))
+	for i, v := range r {
+		s[i] = set.Interval[int]{Lo: int(v.lo), Hi: int(v.hi) + 1}
+	}
+	return s
+}
+
+func fromIntervals(s set.IntervalSet[int]) runs {
+	r := make(runs, len(s))
+	for i, v := range s {
+		r[i] = run{uint16(v.Lo), uint16(v.Hi - 1)}
+	}
+	return r
+}
+
+// combine returns the result of applying o to a and b, choosing a fast
+// path according to their representations, and a compact representation
+// for the result.
+func combine(o op, a, b container) container {
+	switch a := a.(type) {
+	case array:
+		switch {
+		case o == inter:
+			return optimize(a.filter(b, true))
+		case o == diff:
+			return optimize(a.filter(b, false))
+		}
+		if b, ok := b.(array); ok {
+			return optimize(arrayOp(o, a, b))
+		}
+	case *bitmap:
+		if b, ok := b.(*bitmap); ok {
+			return optimize(a.combine(o, b))
+		}
+	case runs:
+		if b, ok := b.(runs); ok {
+			return optimize(fromIntervals(intervalOp(o, a.intervals(), b.intervals())))
+		}
+	}
+	if b, ok := b.(array); ok && o == inter {
+		return optimize(b.filter(a, true))
+	}
+	return optimize(toBitmap(a).combine(o, toBitmap(b)))
+}
+
+func arrayOp(o op, a, b array) array {
+	switch o {
+	case union:
+		return set.UnionInto(nil, a, b)
+	case inter:
+		return set.InterInto(nil, a, b)
+	case diff:
+		return set.DiffInto(nil, a, b)
+	}
+	return set.SymDiffInto(nil, a, b)
+}
+
+func intervalOp(o op, a, b set.IntervalSet[int]) set.IntervalSet[int] {
+	switch o {
+	case union:
+		return a.Union(b)
+	case inter:
+		return a.Inter(b)
+	case diff:
+		return a.Diff(b)
+	}
+	return a.SymDiff(b)
+}
+
+// isSub returns true if every element of a is in b.
+func isSub(a, b container) bool {
+	if a.card() > b.card() {
+		return false
+	}
+	switch a := a.(type) {
+	case *bitmap:
+		if b, ok := b.(*bitmap); ok {
+			for i, w := range a.words {
+				if w&^b.words[i] != 0 {
+					return false
+				}
+			}
+			return true
+		}
+	case runs:
+		if b, ok := b.(runs); ok {
+			return a.intervals().IsSub(b.intervals())
+		}
+	}
+	return a.each(b.contains)
+}
+
+// isInter returns true if any element of a is in b.
+func isInter(a, b container) bool {
+	switch a := a.(type) {
+	case *bitmap:
+		if b, ok := b.(*bitmap); ok {
+			for i, w := range a.words {
+				if w&b.words[i] != 0 {
+					return true
+				}
+			}
+			return false
+		}
+	case runs:
+		if b, ok := b.(runs); ok {
+			return a.intervals().IsInter(b.intervals())
+		}
+	}
+	if a.card() > b.card() {
+		a, b = b, a
+	}
+	return !a.each(func(x uint16) bool { return !b.contains(x) })
+}
+
+func toBitmap(c container) *bitmap {
+	switch c := c.(type) {
+	case *bitmap:
+		return c
+	case runs:
+		b := new(bitmap)
+		for _, v := range c {
+			b.setRange(int(v.lo), int(v.hi))
+		}
+		return b
+	}
+	b := new(bitmap)
+	c.each(func(x uint16) bool {
+		b.add(x)
+		return true
+	})
+	return b
+}
+
+func toArray(c container) array {
+	a := make(array, 0, c.card())
+	c.each(func(x uint16) bool {
+		a = append(a, x)
+		return true
+	})
+	return a
+}
+
+// numRuns returns the number of runs of consecutive elements in c.
+func numRuns(c container) int {
+	n := 0
+	switch c := c.(type) {
+	case runs:
+		return len(c)
+	case *bitmap:
+		var carry uint64 // the top bit of the previous word
+		for _, w := range c.words {
+			n += bits.OnesCount64(w &^ (w<<1 | carry))
+			carry = w >> 63
+		}
+		return n
+	}
+	prev := -2
+	c.each(func(x uint16) bool {
+		if int(x) != prev+1 {
+			n++
+		}
+		prev = int(x)
+		return true
+	})
+	return n
+}
+
+// optimize returns c in whichever representation is smallest, or nil if c
+// is empty.
+func optimize(c container) container {
+	n := c.card()
+	if n == 0 {
+		return nil
+	}
+	r := numRuns(c)
+	switch {
+	case 4*r < min(2*n, 8*bitmapSize):
+		if c, ok := c.(runs); ok {
+			return c
+		}
+		return toRuns(c)
+	case n <= arrayMax:
+		if c, ok := c.(array); ok {
+			return c
+		}
+		return toArray(c)
+	}
+	return toBitmap(c)
+}
+
+func toRuns(c container) runs {
+	var r runs
+	c.each(func(x uint16) bool {
+		if n := len(r) - 1; n >= 0 && int(r[n].hi)+1 == int(x) {
+			r[n].hi = x
+		} else {
+			r = append(r, run{x, x})
+		}
+		return true
+	})
+	return r
+}
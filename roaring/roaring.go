@@ -0,0 +1,228 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package roaring implements a compressed set of ints, in the style of
+// Roaring bitmaps, for sets too large to hold comfortably as []int.
+//
+// Elements are grouped into chunks of 2^16 consecutive values, and each
+// non-empty chunk is stored in whichever of three containers is smallest:
+// a sorted array of 16-bit values for sparse chunks, a 2^16-bit bitmap for
+// dense chunks, or a list of runs for chunks made up of long stretches of
+// consecutive values. Operations between two containers use a fast path
+// specific to their representations, such as word-at-a-time logic between
+// bitmaps.
+//
+// The operations mirror those of package set, and FromInts and Ints
+// convert losslessly to and from the sorted, duplicate-free []int sets
+// that package set produces.
+package roaring
+
+import (
+	"iter"
+	"sort"
+)
+
+// Bitmap is a compressed set of ints. The zero value is an empty set.
+// Methods that return a *Bitmap allocate a new one, leaving their receiver
+// and arguments untouched.
+type Bitmap struct {
+	keys  []int // the high bits of each chunk, in ascending order
+	conts []container
+}
+
+// split returns the chunk key and low 16 bits of x. The key is signed, so
+// chunks of negative values order before those of positive values.
+func split(x int) (key int, low uint16) { return x >> 16, uint16(x) }
+
+// FromInts returns a Bitmap containing the elements of s, which must be
+// sorted and free of duplicates.
+func FromInts(s []int) *Bitmap {
+	b := new(Bitmap)
+	for i := 0; i < len(s); {
+		key, _ := split(s[i])
+		var a array
+		for ; i < len(s); i++ {
+			k, low := split(s[i])
+			if k != key {
+				break
+			}
+			a = append(a, low)
+		}
+		b.keys = append(b.keys, key)
+		b.conts = append(b.conts, optimize(a))
+	}
+	return b
+}
+
+// Ints returns the elements of b as a sorted, duplicate-free slice.
+func (b *Bitmap) Ints() []int {
+	s := make([]int, 0, b.Len())
+	for x := range b.All() {
+		s = append(s, x)
+	}
+	return s
+}
+
+// All returns an iterator over the elements of b, in ascending order.
+func (b *Bitmap) All() iter.Seq[int] {
+	return func(yield func(x int) bool) {
+		for i, c := range b.conts {
+			base := b.keys[i] << 16
+			if !c.each(func(x uint16) bool { return yield(base | int(x)) }) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of elements in b.
+func (b *Bitmap) Len() int {
+	n := 0
+	for _, c := range b.conts {
+		n += c.card()
+	}
+	return n
+}
+
+// find returns the index of the chunk with the given key, and whether it
+// exists.
+func (b *Bitmap) find(key int) (int, bool) {
+	i := sort.SearchInts(b.keys, key)
+	return i, i < len(b.keys) && b.keys[i] == key
+}
+
+// Contains returns true if x is an element of b.
+func (b *Bitmap) Contains(x int) bool {
+	key, low := split(x)
+	i, ok := b.find(key)
+	return ok && b.conts[i].contains(low)
+}
+
+// Add inserts x into b, returning true if it was not already present.
+func (b *Bitmap) Add(x int) bool {
+	key, low := split(x)
+	i, ok := b.find(key)
+	if !ok {
+		b.keys = append(b.keys[:i], append([]int{key}, b.keys[i:]...)...)
+		b.conts = append(b.conts[:i], append([]container{array{low}}, b.conts[i:]...)...)
+		return true
+	}
+	if b.conts[i].contains(low) {
+		return false
+	}
+	b.conts[i] = b.conts[i].add(low)
+	return true
+}
+
+// Remove deletes x from b, returning true if it was present.
+func (b *Bitmap) Remove(x int) bool {
+	key, low := split(x)
+	i, ok := b.find(key)
+	if !ok || !b.conts[i].contains(low) {
+		return false
+	}
+	c := b.conts[i].remove(low)
+	if c == nil || c.card() == 0 {
+		b.keys = append(b.keys[:i], b.keys[i+1:]...)
+		b.conts = append(b.conts[:i], b.conts[i+1:]...)
+		return true
+	}
+	b.conts[i] = c
+	return true
+}
+
+// Union returns the set of elements in either b or c.
+func (b *Bitmap) Union(c *Bitmap) *Bitmap { return b.combine(union, c) }
+
+// Inter returns the set of elements in both b and c.
+func (b *Bitmap) Inter(c *Bitmap) *Bitmap { return b.combine(inter, c) }
+
+// Diff returns the set of elements in b but not in c.
+func (b *Bitmap) Diff(c *Bitmap) *Bitmap { return b.combine(diff, c) }
+
+// SymDiff returns the set of elements in exactly one of b and c.
+func (b *Bitmap) SymDiff(c *Bitmap) *Bitmap { return b.combine(symDiff, c) }
+
+// IsSub returns true only if all elements of b are also in c.
+func (b *Bitmap) IsSub(c *Bitmap) bool {
+	j := 0
+	for i, key := range b.keys {
+		for j < len(c.keys) && c.keys[j] < key {
+			j++
+		}
+		if j == len(c.keys) || c.keys[j] != key || !isSub(b.conts[i], c.conts[j]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuper returns true only if all elements of c are also in b.
+func (b *Bitmap) IsSuper(c *Bitmap) bool { return c.IsSub(b) }
+
+// IsInter returns true if any element of b is also in c.
+func (b *Bitmap) IsInter(c *Bitmap) bool {
+	i, j := 0, 0
+	for i < len(b.keys) && j < len(c.keys) {
+		switch {
+		case b.keys[i] < c.keys[j]:
+			i++
+		case b.keys[i] > c.keys[j]:
+			j++
+		case isInter(b.conts[i], c.conts[j]):
+			return true
+		default:
+			i, j = i+1, j+1
+		}
+	}
+	return false
+}
+
+// IsEqual returns true if b and c contain the same elements.
+func (b *Bitmap) IsEqual(c *Bitmap) bool {
+	if len(b.keys) != len(c.keys) {
+		return false
+	}
+	for i, key := range b.keys {
+		if c.keys[i] != key || b.conts[i].card() != c.conts[i].card() {
+			return false
+		}
+	}
+	return b.IsSub(c)
+}
+
+// combine merges the chunks of b and c, applying o to chunks present in
+// both, and copying those present in only one as o requires.
+func (b *Bitmap) combine(o op, c *Bitmap) *Bitmap {
+	d := new(Bitmap)
+	push := func(key int, cont container) {
+		if cont != nil {
+			d.keys = append(d.keys, key)
+			d.conts = append(d.conts, cont)
+		}
+	}
+
+	keepB := o != inter                 // keep chunks only in b
+	keepC := o == union || o == symDiff // keep chunks only in c
+
+	i, j := 0, 0
+	for i < len(b.keys) || j < len(c.keys) {
+		switch {
+		case j == len(c.keys) || i < len(b.keys) && b.keys[i] < c.keys[j]:
+			if keepB {
+				push(b.keys[i], b.conts[i].clone())
+			}
+			i++
+		case i == len(b.keys) || c.keys[j] < b.keys[i]:
+			if keepC {
+				push(c.keys[j], c.conts[j].clone())
+			}
+			j++
+		default:
+			push(b.keys[i], combine(o, b.conts[i], c.conts[j]))
+			i, j = i+1, j+1
+		}
+	}
+	return d
+}
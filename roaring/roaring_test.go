@@ -0,0 +1,276 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package roaring_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/testdata"
+	"github.com/xtgo/set/roaring"
+)
+
+// randInts returns a set mixing sparse elements, dense regions and long
+// runs, spread over several chunks on either side of zero.
+func randInts(rng *rand.Rand) []int {
+	var s []int
+	for chunk := -2; chunk < 3; chunk++ {
+		base := chunk << 16
+		switch rng.Intn(4) {
+		case 0: // sparse
+			for i := rng.Intn(100); i > 0; i-- {
+				s = append(s, base+rng.Intn(1<<16))
+			}
+		case 1: // dense
+			for x := 0; x < 1<<16; x++ {
+				if rng.Intn(3) == 0 {
+					s = append(s, base+x)
+				}
+			}
+		case 2: // runs
+			for i := rng.Intn(8); i > 0; i-- {
+				lo := rng.Intn(1 << 16)
+				hi := min(lo+rng.Intn(10000), 1<<16)
+				for x := lo; x < hi; x++ {
+					s = append(s, base+x)
+				}
+			}
+		}
+	}
+	return set.Ints(s)
+}
+
+func TestBitmap(t *testing.T) {
+	type B = *roaring.Bitmap
+
+	ops := map[string]set.Op{
+		"Union":   set.Union,
+		"Inter":   set.Inter,
+		"Diff":    set.Diff,
+		"SymDiff": set.SymDiff,
+	}
+	muts := map[string]func(a, b B) B{
+		"Union":   B.Union,
+		"Inter":   B.Inter,
+		"Diff":    B.Diff,
+		"SymDiff": B.SymDiff,
+	}
+	cmps := map[string]set.Cmp{
+		"IsSub":   set.IsSub,
+		"IsSuper": set.IsSuper,
+		"IsInter": set.IsInter,
+		"IsEqual": set.IsEqual,
+	}
+	bools := map[string]func(a, b B) bool{
+		"IsSub":   B.IsSub,
+		"IsSuper": B.IsSuper,
+		"IsInter": B.IsInter,
+		"IsEqual": B.IsEqual,
+	}
+
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 20; i++ {
+		a, b := randInts(rng), randInts(rng)
+		if i%5 == 0 {
+			b = a[: len(a)/2 : len(a)/2]
+		}
+		ra, rb := roaring.FromInts(a), roaring.FromInts(b)
+
+		if got := ra.Ints(); !testdata.IsEqual(got, a) {
+			t.Fatalf("round trip of %d elements gave %d", len(a), len(got))
+		}
+
+		for name, op := range muts {
+			want := set.Do(ops[name], append([]int(nil), a...), b...)
+			got := op(ra, rb)
+
+			if !testdata.IsEqual(got.Ints(), want) || got.Len() != len(want) {
+				t.Fatalf("%s: got %d elements, want %d", name, got.Len(), len(want))
+			}
+		}
+
+		for name, op := range bools {
+			want := set.Chk(cmps[name], a, b...)
+
+			if got := op(ra, rb); got != want {
+				t.Fatalf("%s = %t, want %t", name, got, want)
+			}
+		}
+	}
+}
+
+// manyRuns returns a single chunk of 1024 short runs, which is stored as a
+// run container.
+func manyRuns() []int {
+	var s []int
+	for lo := 0; lo < 1<<16; lo += 64 {
+		s = append(s, lo, lo+1, lo+2)
+	}
+	return s
+}
+
+// sparse returns n elements spread across the same chunk as manyRuns,
+// which are stored as an array container.
+func sparse(rng *rand.Rand, n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = rng.Intn(1 << 16)
+	}
+	return set.Ints(s)
+}
+
+func TestArrayRuns(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	r := manyRuns()
+	rr := roaring.FromInts(r)
+
+	for i := 0; i < 20; i++ {
+		a := sparse(rng, 500)
+		ra := roaring.FromInts(a)
+
+		for name, got := range map[string]*roaring.Bitmap{
+			"Inter": ra.Inter(rr),
+			"Diff":  ra.Diff(rr),
+		} {
+			want := set.Do(map[string]set.Op{"Inter": set.Inter, "Diff": set.Diff}[name], append([]int(nil), a...), r...)
+			if !testdata.IsEqual(got.Ints(), want) {
+				t.Fatalf("%s: got %d elements, want %d", name, got.Len(), len(want))
+			}
+		}
+		sub := a[:0:0]
+		for _, x := range a {
+			if rr.Contains(x) {
+				sub = append(sub, x)
+			}
+		}
+		if !roaring.FromInts(sub).IsSub(rr) || (len(sub) < len(a) && ra.IsSub(rr)) {
+			t.Fatalf("IsSub disagrees with Contains")
+		}
+	}
+
+	if n := testing.AllocsPerRun(100, func() { rr.Contains(12345) }); n != 0 {
+		t.Errorf("Contains on a run container allocated %v times", n)
+	}
+}
+
+func TestRunsAddRemove(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	ref := map[int]bool{}
+	var s []int
+	for lo := 0; lo < 1<<16; lo += 1000 {
+		for x := lo; x < lo+500; x++ {
+			s = append(s, x)
+			ref[x] = true
+		}
+	}
+	b := roaring.FromInts(s)
+
+	// stay near the run boundaries, so that runs are extended, merged,
+	// split and shrunk rather than the chunk changing form
+	for i := 0; i < 20000; i++ {
+		x := 1000*rng.Intn(1<<16/1000+1) + rng.Intn(7) - 3
+		if x < 0 || x >= 1<<16 {
+			continue
+		}
+		if rng.Intn(2) == 0 {
+			if b.Remove(x) != ref[x] {
+				t.Fatalf("Remove(%d) reported the wrong status", x)
+			}
+			delete(ref, x)
+		} else {
+			if b.Add(x) == ref[x] {
+				t.Fatalf("Add(%d) reported the wrong status", x)
+			}
+			ref[x] = true
+		}
+	}
+
+	if b.Len() != len(ref) {
+		t.Fatalf("Len() = %d, want %d", b.Len(), len(ref))
+	}
+	for x := range b.All() {
+		if !ref[x] {
+			t.Fatalf("unexpected element %d", x)
+		}
+	}
+	for x := range ref {
+		if !b.Contains(x) {
+			t.Fatalf("missing element %d", x)
+		}
+	}
+}
+
+func BenchmarkContainsRuns(b *testing.B) {
+	rr := roaring.FromInts(manyRuns())
+	for i := 0; i < b.N; i++ {
+		rr.Contains(i & (1<<16 - 1))
+	}
+}
+
+func BenchmarkAddRemoveRuns(b *testing.B) {
+	rr := roaring.FromInts(manyRuns())
+	for i := 0; i < b.N; i++ {
+		x := i*64&(1<<16-1) + 3
+		rr.Add(x)
+		rr.Remove(x)
+	}
+}
+
+func BenchmarkInterArrayRuns(b *testing.B) {
+	ra := roaring.FromInts(sparse(rand.New(rand.NewSource(0)), 2000))
+	rr := roaring.FromInts(manyRuns())
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ra.Inter(rr)
+	}
+}
+
+func TestBitmapAddRemove(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	ref := map[int]bool{}
+	b := new(roaring.Bitmap)
+
+	// concentrate on one chunk, so that its container changes form
+	for i := 0; i < 40000; i++ {
+		x := rng.Intn(6000) - 100
+		if rng.Intn(3) == 0 {
+			if b.Remove(x) != ref[x] {
+				t.Fatalf("Remove(%d) reported the wrong status", x)
+			}
+			delete(ref, x)
+		} else {
+			if b.Add(x) == ref[x] {
+				t.Fatalf("Add(%d) reported the wrong status", x)
+			}
+			ref[x] = true
+		}
+	}
+
+	if b.Len() != len(ref) {
+		t.Fatalf("Len() = %d, want %d", b.Len(), len(ref))
+	}
+	for x := range b.All() {
+		if !ref[x] || !b.Contains(x) {
+			t.Fatalf("unexpected element %d", x)
+		}
+	}
+}
+
+func Example() {
+	evens := make([]int, 0, 50000)
+	for x := 0; x < 100000; x += 2 {
+		evens = append(evens, x)
+	}
+
+	a := roaring.FromInts(evens)
+	b := roaring.FromInts(set.Ints([]int{3, 4, 5, 99998, 100000}))
+
+	fmt.Println(a.Len(), a.Inter(b).Ints(), a.IsSuper(b))
+
+	// Output: 50000 [4 99998] false
+}
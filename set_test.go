@@ -5,8 +5,10 @@
 package set_test
 
 import (
+	"math/rand"
 	"testing"
 
+	"github.com/xtgo/set/internal/mapset"
 	"github.com/xtgo/set/internal/sliceset"
 	"github.com/xtgo/set/internal/testdata"
 )
@@ -66,3 +68,24 @@ func testBool(t *testing.T, name string) {
 		}
 	}
 }
+
+func TestRand(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+
+	for _, name := range []string{"Union", "Inter", "Diff", "SymDiff"} {
+		var op mutOp
+		var ref func(a, b mapset.Set) mapset.Set
+		testdata.ConvMethod(&op, sliceset.Set(nil), name)
+		testdata.ConvMethod(&ref, mapset.Set(nil), name)
+
+		for i := 0; i < 1000; i++ {
+			a, b := sliceset.Set(testdata.RandSet(rng)), sliceset.Set(testdata.RandSet(rng))
+			want := ref(mapset.New(a), mapset.New(b)).Elems()
+			c := op(a.Copy(), b)
+
+			if !testdata.IsEqual(c, want) {
+				t.Fatalf(format, name, a, b, c, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package settest checks that a sort.Interface implementation behaves
+// correctly when used with package set.
+//
+// Bugs in a type's Less or Swap methods, such as comparing the wrong
+// fields or swapping only some of them, rarely cause a panic; instead, the
+// set operations quietly produce wrong results. Check runs the operations
+// of package set over the type with a variety of edge-case and randomized
+// inputs, verifying the results against a map-based reference and
+// against the laws of set algebra.
+package settest
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/xtgo/set"
+	"github.com/xtgo/set/internal/mapset"
+	"github.com/xtgo/set/internal/testdata"
+)
+
+// TB is the subset of testing.TB used to report failures.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Type describes the sort.Interface implementation under test in terms of
+// ints. Its functions must preserve order: for ints x and y, the element
+// made from x must be less than the element made from y if and only if x
+// is less than y.
+type Type struct {
+	// New returns data holding elements corresponding to s, in order.
+	New func(s []int) sort.Interface
+
+	// Ints returns the ints corresponding to all of the elements of data,
+	// in order.
+	Ints func(data sort.Interface) []int
+}
+
+// Check verifies the set operations over typ, reporting any failures to
+// t. At most one failure is reported for each operation and law.
+//
+// Besides agreement with a reference implementation, Check verifies that
+// Union, Inter, and SymDiff are commutative and associative, that De
+// Morgan's laws hold, and that Apply agrees with applying an operation
+// pairwise. Apply runs operations concurrently, so typ's Less and Swap
+// methods must be safe for concurrent use on disjoint indices.
+func Check(t TB, typ Type) {
+	t.Helper()
+	c := &checker{t: t, typ: typ, failed: make(map[string]bool)}
+	for _, in := range inputs() {
+		c.reference(in[0], in[1])
+		c.commutative(in[0], in[1])
+		c.associative(in[0], in[1], in[2])
+		c.deMorgan(in[0], in[1], in[2])
+		c.apply(in[:])
+	}
+}
+
+var (
+	ops = []struct {
+		name string
+		op   set.Op
+		ref  func(a, b mapset.Set) mapset.Set
+	}{
+		{"Union", set.Union, mapset.Set.Union},
+		{"Inter", set.Inter, mapset.Set.Inter},
+		{"Diff", set.Diff, mapset.Set.Diff},
+		{"SymDiff", set.SymDiff, mapset.Set.SymDiff},
+	}
+	cmps = []struct {
+		name string
+		cmp  set.Cmp
+		ref  func(a, b mapset.Set) bool
+	}{
+		{"IsSub", set.IsSub, mapset.Set.IsSub},
+		{"IsSuper", set.IsSuper, mapset.Set.IsSuper},
+		{"IsInter", set.IsInter, mapset.Set.IsInter},
+		{"IsEqual", set.IsEqual, mapset.Set.IsEqual},
+	}
+)
+
+// inputs returns triples of sets drawn from the edge cases of
+// testdata.BinTests and from random sets of varying size and density.
+func inputs() [][3][]int {
+	var in [][3][]int
+	tests := testdata.BinTests
+	for i, tt := range tests {
+		next := tests[(i+1)%len(tests)]
+		in = append(in, [3][]int{tt.A, tt.B, next.A})
+	}
+
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 200; i++ {
+		in = append(in, [3][]int{testdata.RandSet(rng), testdata.RandSet(rng), testdata.RandSet(rng)})
+	}
+	return in
+}
+
+type checker struct {
+	t      TB
+	typ    Type
+	failed map[string]bool
+}
+
+// errorf reports a failure, unless one was already reported for key.
+func (c *checker) errorf(key, format string, args ...any) {
+	c.t.Helper()
+	if c.failed[key] {
+		return
+	}
+	c.failed[key] = true
+	c.t.Errorf(format, args...)
+}
+
+// do applies op to a and b, represented as typ, returning the result. If
+// op panics, as it may when built with the setdebug tag, the panic is
+// reported and the result is nil.
+func (c *checker) do(op set.Op, a, b []int) (s []int) {
+	defer c.recover(a, b)
+	data := c.typ.New(append(append([]int(nil), a...), b...))
+	return c.ints(data, op(data, len(a)))
+}
+
+// ints returns the first n elements of data, reporting a failure if Ints
+// returns too few.
+func (c *checker) ints(data sort.Interface, n int) []int {
+	s := c.typ.Ints(data)
+	if len(s) != data.Len() || n > len(s) {
+		c.errorf("Ints", "Ints returned %d elements for data of length %d", len(s), data.Len())
+		return s[:min(n, len(s))]
+	}
+	return s[:n]
+}
+
+func (c *checker) chk(cmp set.Cmp, a, b []int) bool {
+	defer c.recover(a, b)
+	data := c.typ.New(append(append([]int(nil), a...), b...))
+	return cmp(data, len(a))
+}
+
+func (c *checker) recover(a, b []int) {
+	if r := recover(); r != nil {
+		c.errorf("panic", "panic with sets %v and %v: %v", a, b, r)
+	}
+}
+
+func (c *checker) reference(a, b []int) {
+	c.t.Helper()
+	for _, o := range ops {
+		got := c.do(o.op, a, b)
+		want := o.ref(mapset.New(a), mapset.New(b)).Elems()
+		if !testdata.IsEqual(got, want) {
+			c.errorf(o.name, "%s(%v, %v) = %v, want %v", o.name, a, b, got, want)
+		}
+	}
+	for _, o := range cmps {
+		got := c.chk(o.cmp, a, b)
+		want := o.ref(mapset.New(a), mapset.New(b))
+		if got != want {
+			c.errorf(o.name, "%s(%v, %v) = %t, want %t", o.name, a, b, got, want)
+		}
+	}
+}
+
+func (c *checker) commutative(a, b []int) {
+	c.t.Helper()
+	for _, o := range ops {
+		if o.name == "Diff" {
+			continue
+		}
+		ab, ba := c.do(o.op, a, b), c.do(o.op, b, a)
+		if !testdata.IsEqual(ab, ba) {
+			c.errorf(o.name+" commutative",
+				"%s is not commutative: %s(%v, %v) = %v, but %s(%v, %v) = %v",
+				o.name, o.name, a, b, ab, o.name, b, a, ba)
+		}
+	}
+}
+
+func (c *checker) associative(a, b, d []int) {
+	c.t.Helper()
+	for _, o := range ops {
+		if o.name == "Diff" {
+			continue
+		}
+		l := c.do(o.op, c.do(o.op, a, b), d)
+		r := c.do(o.op, a, c.do(o.op, b, d))
+		if !testdata.IsEqual(l, r) {
+			c.errorf(o.name+" associative",
+				"%s is not associative over (%v, %v, %v): %v grouped left, %v grouped right",
+				o.name, a, b, d, l, r)
+		}
+	}
+}
+
+// deMorgan checks De Morgan's laws, taking complements relative to the
+// union of a, b, and d.
+func (c *checker) deMorgan(a, b, d []int) {
+	c.t.Helper()
+	u := c.do(set.Union, c.do(set.Union, a, b), d)
+	na, nb := c.do(set.Diff, u, a), c.do(set.Diff, u, b)
+
+	l := c.do(set.Diff, u, c.do(set.Union, a, b))
+	r := c.do(set.Inter, na, nb)
+	if !testdata.IsEqual(l, r) {
+		c.errorf("De Morgan union",
+			"De Morgan's law fails for %v and %v within %v: complement of union is %v, intersection of complements is %v",
+			a, b, u, l, r)
+	}
+
+	l = c.do(set.Diff, u, c.do(set.Inter, a, b))
+	r = c.do(set.Union, na, nb)
+	if !testdata.IsEqual(l, r) {
+		c.errorf("De Morgan inter",
+			"De Morgan's law fails for %v and %v within %v: complement of intersection is %v, union of complements is %v",
+			a, b, u, l, r)
+	}
+}
+
+// apply checks that Apply over sets agrees with applying op to each set in
+// turn. Apply is skipped once any other failure has been reported, since
+// it runs op in goroutines, where a panic cannot be recovered.
+func (c *checker) apply(sets [][]int) {
+	c.t.Helper()
+	if len(c.failed) > 0 {
+		return
+	}
+	var all []int
+	sizes := make([]int, len(sets))
+	for i, s := range sets {
+		all = append(all, s...)
+		sizes[i] = len(s)
+	}
+	for _, o := range ops {
+		if o.name == "Diff" {
+			continue
+		}
+		want := sets[0]
+		for _, s := range sets[1:] {
+			want = c.do(o.op, want, s)
+		}
+		data := c.typ.New(append([]int(nil), all...))
+		n := set.Apply(o.op, data, set.Pivots(append([]int(nil), sizes...)...))
+		got := c.ints(data, n)
+		if !testdata.IsEqual(got, want) {
+			c.errorf(o.name+" Apply", "Apply(%s, %v) = %v, want %v", o.name, sets, got, want)
+		}
+	}
+}
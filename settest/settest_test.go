@@ -0,0 +1,97 @@
+// Copyright 2015 Kevin Gillette. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package settest_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/xtgo/set/settest"
+)
+
+var intSlice = settest.Type{
+	New:  func(s []int) sort.Interface { return sort.IntSlice(s) },
+	Ints: func(data sort.Interface) []int { return data.(sort.IntSlice) },
+}
+
+func TestCheck(t *testing.T) { settest.Check(t, intSlice) }
+
+// fakeTB records failures instead of reporting them.
+type fakeTB struct{ errs []string }
+
+func (t *fakeTB) Helper() {}
+
+func (t *fakeTB) Errorf(format string, args ...any) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
+
+// record is a sort.Interface over parallel slices, as is common for
+// struct-of-arrays data.
+type record struct {
+	keys []int
+	vals []string
+}
+
+func (r record) Len() int           { return len(r.keys) }
+func (r record) Less(i, j int) bool { return r.keys[i] < r.keys[j] }
+func (r record) Swap(i, j int)      { r.keys[i], r.keys[j] = r.keys[j], r.keys[i] }
+
+// badSwap forgets to swap vals, so vals no longer follow their keys.
+var badSwap = settest.Type{
+	New: func(s []int) sort.Interface {
+		r := record{keys: s}
+		for _, k := range s {
+			r.vals = append(r.vals, fmt.Sprint(k))
+		}
+		return r
+	},
+	Ints: func(data sort.Interface) []int {
+		var s []int
+		for _, v := range data.(record).vals {
+			var k int
+			fmt.Sscan(v, &k)
+			s = append(s, k)
+		}
+		return s
+	},
+}
+
+// lessEq implements Less as less-than-or-equal.
+type lessEq []int
+
+func (s lessEq) Len() int           { return len(s) }
+func (s lessEq) Less(i, j int) bool { return s[i] <= s[j] }
+func (s lessEq) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func TestCheckBroken(t *testing.T) {
+	types := map[string]settest.Type{
+		"badSwap": badSwap,
+		"lessEq": {
+			New:  func(s []int) sort.Interface { return lessEq(s) },
+			Ints: func(data sort.Interface) []int { return data.(lessEq) },
+		},
+	}
+	for name, typ := range types {
+		tb := new(fakeTB)
+		settest.Check(tb, typ)
+
+		if len(tb.errs) == 0 {
+			t.Errorf("Check(%s) reported no failures", name)
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, e := range tb.errs {
+			if seen[e] {
+				t.Errorf("Check(%s) reported %q more than once", name, e)
+			}
+			seen[e] = true
+		}
+		if !strings.Contains(strings.Join(tb.errs, "\n"), "want") {
+			t.Errorf("Check(%s) reported no disagreement with the reference: %q", name, tb.errs)
+		}
+	}
+}